@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/skygeario/openapi3-gen/pkg/split"
+	"gopkg.in/yaml.v2"
+)
+
+func runBundle(args []string) error {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	output := flags.String("output", "", "output OpenAPI specification file (stdout if empty)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s bundle [flags] <file>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitError{2}
+	}
+
+	doc, err := split.Bundle(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*output, data)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/skygeario/openapi3-gen/pkg/diff"
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"gopkg.in/yaml.v2"
+)
+
+// checkOutput compares the generated output with the existing output file,
+// printing a unified diff if they differ. YAML documents are compared
+// semantically, ignoring key order and formatting.
+func checkOutput(outputFile string, data []byte, format string) error {
+	if outputFile == "" {
+		return fmt.Errorf("-check requires -output")
+	}
+
+	existing, err := ioutil.ReadFile(outputFile)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "%s does not exist\n", outputFile)
+		return exitError{1}
+	} else if err != nil {
+		return err
+	}
+
+	if format == "" || format == "yaml" || format == "swagger2" {
+		existing, data, err = canonicalDocuments(existing, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	unified := diff.Unified(outputFile, outputFile+" (generated)", string(existing), string(data))
+	if unified == "" {
+		return nil
+	}
+	fmt.Fprint(os.Stdout, unified)
+	fmt.Fprintf(os.Stderr, "%s is out of date\n", outputFile)
+	return exitError{1}
+}
+
+// canonicalDocuments re-serializes the YAML documents with sorted keys, or
+// returns identical data if they are semantically equal.
+func canonicalDocuments(a []byte, b []byte) ([]byte, []byte, error) {
+	docA, err := openapi3.ReadDocument(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	docB, err := openapi3.ReadDocument(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if jsonschema.Equal(docA, docB) {
+		return b, b, nil
+	}
+
+	if a, err = yaml.Marshal(docA); err != nil {
+		return nil, nil, err
+	}
+	if b, err = yaml.Marshal(docB); err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/skygeario/openapi3-gen/pkg/codegen"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+type codegenFunc func(oapi *openapi3.OpenAPIObject, config codegen.Config) ([]byte, error)
+
+func runGenerateServer(args []string) error {
+	return runCodegen("generate-server", codegen.GenerateServer, args)
+}
+
+func runGenerateClient(args []string) error {
+	return runCodegen("generate-client", codegen.GenerateClient, args)
+}
+
+func runCodegen(name string, gen codegenFunc, args []string) error {
+	var opts runOptions
+	var config codegen.Config
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	dir := flags.String("dir", workingDir(), "project base directory")
+	output := flags.String("output", "", "output Go source file (stdout if empty)")
+	flags.StringVar(&config.PackageName, "package", "api", "package name of generated code")
+	flags.BoolVar(&opts.Validate, "validate", true, "validate the generated document against the OpenAPI 3.0 meta-schema")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s %s [flags] <patterns...>\n", os.Args[0], name)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		flags.Usage()
+		return exitError{2}
+	}
+
+	oapi, err := generate(*dir, patterns, opts)
+	if err != nil {
+		return err
+	}
+
+	src, err := gen(oapi, config)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*output, src)
+}
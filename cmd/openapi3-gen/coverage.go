@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+
+	"github.com/skygeario/openapi3-gen/pkg/coverage"
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	"github.com/skygeario/openapi3-gen/pkg/scanner"
+)
+
+func runCoverage(args []string) error {
+	var signatureStrs stringList
+	flags := flag.NewFlagSet("coverage", flag.ExitOnError)
+	dir := flags.String("dir", workingDir(), "project base directory")
+	format := flags.String("format", "text", "report format (text, json)")
+	threshold := flags.Float64("threshold", 0, "minimum coverage percentage")
+	flags.Var(&signatureStrs, "signature", "handler function signature (default \""+coverage.DefaultSignature+"\"; repeatable)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s coverage [flags] <patterns...>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		flags.Usage()
+		return exitError{2}
+	}
+
+	if len(signatureStrs) == 0 {
+		signatureStrs = stringList{coverage.DefaultSignature}
+	}
+	var signatures []coverage.Signature
+	for _, str := range signatureStrs {
+		sig, err := coverage.ParseSignature(str)
+		if err != nil {
+			return err
+		}
+		signatures = append(signatures, sig)
+	}
+
+	psr := processor.New()
+	finder := coverage.NewFinder(signatures)
+	scn := scanner.New(func(fset *token.FileSet, file *ast.File) {
+		psr.Process(fset, file)
+		finder.Process(fset, file)
+	})
+
+	err := scn.Scan(*dir, patterns)
+	if err != nil {
+		return err
+	}
+
+	_, errs := psr.End()
+	if len(errs) > 0 {
+		return runnerError{errs}
+	}
+
+	report := finder.Report(psr.Operations())
+	switch *format {
+	case "text":
+		err = report.WriteText(os.Stdout)
+	case "json":
+		err = report.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown report format: %v", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if report.Coverage() < *threshold {
+		fmt.Fprintf(os.Stderr, "coverage %.1f%% is below threshold %.1f%%\n", report.Coverage(), *threshold)
+		return exitError{1}
+	}
+	return nil
+}
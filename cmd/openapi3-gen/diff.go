@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skygeario/openapi3-gen/pkg/diff"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	dir := flags.String("dir", workingDir(), "project base directory")
+	baseFile := flags.String("base", "", "base OpenAPI specification file")
+	baseRef := flags.String("base-ref", "", "git revision of the project to generate base specification from")
+	format := flags.String("format", "text", "report format (text, markdown, json)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s diff [flags] <patterns...>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	patterns := flags.Args()
+	if len(patterns) == 0 || (*baseFile == "") == (*baseRef == "") {
+		flags.Usage()
+		return exitError{2}
+	}
+
+	var base interface{}
+	var err error
+	if *baseFile != "" {
+		base, err = readDocumentFile(*baseFile)
+	} else {
+		base, err = generateAtRevision(*dir, *baseRef, patterns)
+	}
+	if err != nil {
+		return err
+	}
+
+	oapi, err := generate(*dir, patterns, runOptions{})
+	if err != nil {
+		return err
+	}
+	revision, err := openapi3.ToDocument(oapi)
+	if err != nil {
+		return err
+	}
+
+	report := &diff.Report{Changes: diff.Compare(base, revision)}
+	switch *format {
+	case "text":
+		err = report.WriteText(os.Stdout)
+	case "markdown":
+		err = report.WriteMarkdown(os.Stdout)
+	case "json":
+		err = report.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown report format: %v", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(report.Breaking()) > 0 {
+		return exitError{1}
+	}
+	return nil
+}
+
+func readDocumentFile(path string) (interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.ReadDocument(data)
+}
+
+// generateAtRevision generates the document from the project checked out at
+// the git revision in a temporary worktree.
+func generateAtRevision(dir string, rev string, patterns []string) (interface{}, error) {
+	git := func(args ...string) (string, error) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", errors.Wrapf(err, "git %s", strings.Join(args, " "))
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	prefix, err := git("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir, err := ioutil.TempDir("", "openapi3-gen-diff")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	worktree := filepath.Join(tempDir, "worktree")
+	if _, err := git("worktree", "add", "--quiet", "--detach", worktree, rev); err != nil {
+		return nil, err
+	}
+	defer git("worktree", "remove", "--force", worktree)
+
+	oapi, err := generate(filepath.Join(worktree, prefix), patterns, runOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate specification at %s", rev)
+	}
+	return openapi3.ToDocument(oapi)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/skygeario/openapi3-gen/pkg/lint"
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	"github.com/skygeario/openapi3-gen/pkg/scanner"
+)
+
+func runLint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	dir := flags.String("dir", workingDir(), "project base directory")
+	configFile := flags.String("config", "", "lint configuration file")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s lint [flags] <patterns...>\n", os.Args[0])
+		flags.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "rules:\n")
+		for _, rule := range lint.Rules {
+			fmt.Fprintf(os.Stderr, "  %s (%s)\n    \t%s\n", rule.Name, rule.Severity, rule.Description)
+		}
+	}
+	flags.Parse(args)
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		flags.Usage()
+		return exitError{2}
+	}
+
+	var config *lint.Config
+	if *configFile != "" {
+		var err error
+		config, err = lint.LoadConfig(*configFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	psr := processor.New()
+	scn := scanner.New(psr.Process)
+	err := scn.Scan(*dir, patterns)
+	if err != nil {
+		return err
+	}
+
+	oapi, errs := psr.End()
+	if len(errs) > 0 {
+		return runnerError{errs}
+	}
+
+	diagnostics := lint.Lint(oapi, config, psr.SourceMap())
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintln(os.Stdout, diagnostic)
+	}
+	if lint.HasErrors(diagnostics) {
+		return exitError{1}
+	}
+	return nil
+}
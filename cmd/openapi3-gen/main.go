@@ -4,25 +4,123 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
 )
 
 var baseDir string
 var outputFile string
+var options runOptions
 
-func init() {
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"bundle": {
+		usage: "bundle a split specification into a single file",
+		run:   runBundle,
+	},
+	"coverage": {
+		usage: "report handlers without operation annotations",
+		run:   runCoverage,
+	},
+	"diff": {
+		usage: "report changes to the specification and whether they are breaking",
+		run:   runDiff,
+	},
+	"generate-client": {
+		usage: "generate Go client and types",
+		run:   runGenerateClient,
+	},
+	"generate-server": {
+		usage: "generate Go server interface and types",
+		run:   runGenerateServer,
+	},
+	"lint": {
+		usage: "check API style rules",
+		run:   runLint,
+	},
+	"mock": {
+		usage: "serve examples of the specification as a mock API",
+		run:   runMock,
+	},
+	"validate": {
+		usage: "validate an OpenAPI document against the OpenAPI 3.0 meta-schema",
+		run:   runValidate,
+	},
+}
+
+// exitError terminates the program with the specified status code after the
+// command has reported its result.
+type exitError struct {
+	code int
+}
+
+func (err exitError) Error() string {
+	return fmt.Sprintf("exit status %d", err.code)
+}
+
+// stringList is a flag value that can be specified multiple times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func workingDir() string {
 	workDir, err := os.Getwd()
 	if err != nil {
 		panic(err)
 	}
+	return workDir
+}
 
-	flag.StringVar(&baseDir, "dir", workDir, "project base directory")
-	flag.StringVar(&outputFile, "output", "", "output OpenAPI specification file (stdout if empty)")
+func init() {
+	flag.StringVar(&baseDir, "dir", workingDir(), "project base directory")
+	flag.StringVar(&outputFile, "output", "", "output OpenAPI specification file (stdout if empty), or directory of html format and -split")
+	flag.StringVar(&options.Format, "format", "yaml", "output format (yaml, swagger2, typescript, html, markdown, postman)")
+	flag.StringVar(&options.Templates, "templates", "", "directory of templates replacing built-in templates of html and markdown formats")
+	flag.BoolVar(&options.Split, "split", false, "write output as multiple files in output directory (yaml, markdown)")
+	flag.StringVar(&options.InferParams, "infer-params", "", "infer parameters read by handlers (warn, add)")
+	flag.StringVar(&options.Base, "base", "", "base OpenAPI specification file to merge generated specification into")
+	flag.StringVar((*string)(&options.MergeStrategy), "merge-strategy", string(openapi3.MergeStrategyError), "resolution of conflicting definitions in base specification (error, prefer-base, prefer-source)")
+	flag.Var((*stringList)(&options.Overlays), "overlay", "overlay document file to apply to generated specification (repeatable)")
+	flag.BoolVar(&options.SynthesizeExamples, "synthesize-examples", false, "add examples generated from schemas to media types without examples")
+	flag.BoolVar(&options.Check, "check", false, "compare output with the existing output file, without writing it")
+	flag.StringVar(&options.OpenAPIVersion, "openapi-version", "3.0", "OpenAPI version of generated specification (3.0, 3.1)")
+	flag.BoolVar(&options.Validate, "validate", true, "validate the generated document against the OpenAPI 3.0 meta-schema (ignored for OpenAPI 3.1)")
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			runCommand(cmd, os.Args[2:])
+			return
+		}
+	}
+
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "usage: %s [flags] <patterns...>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s <command> [flags] <args...>\n", os.Args[0])
 		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "commands:\n")
+		var names []string
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(os.Stderr, "  %s\n    \t%s\n", name, commands[name].usage)
+		}
 	}
 
 	flag.Parse()
@@ -33,8 +131,20 @@ func main() {
 		os.Exit(2)
 	}
 
-	err := run(baseDir, patterns, outputFile)
-	if err != nil {
+	err := run(baseDir, patterns, outputFile, options)
+	if exit, ok := err.(exitError); ok {
+		os.Exit(exit.code)
+	} else if err != nil {
 		panic(err)
 	}
 }
+
+func runCommand(cmd command, args []string) {
+	err := cmd.run(args)
+	if exit, ok := err.(exitError); ok {
+		os.Exit(exit.code)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
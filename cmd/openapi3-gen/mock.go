@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/skygeario/openapi3-gen/pkg/mock"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+func runMock(args []string) error {
+	flags := flag.NewFlagSet("mock", flag.ExitOnError)
+	dir := flags.String("dir", workingDir(), "project base directory")
+	specFile := flags.String("spec", "", "OpenAPI specification file to serve, instead of scanning packages")
+	addr := flags.String("addr", "localhost:4010", "address to listen on")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s mock [flags] <patterns...>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s mock [flags] -spec <file>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var oapi *openapi3.OpenAPIObject
+	var err error
+	switch {
+	case *specFile != "":
+		var data []byte
+		data, err = ioutil.ReadFile(*specFile)
+		if err != nil {
+			return err
+		}
+		oapi, err = openapi3.Load(data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load %s", *specFile)
+		}
+	case flags.NArg() > 0:
+		oapi, err = generate(*dir, flags.Args(), runOptions{})
+		if err != nil {
+			return err
+		}
+	default:
+		flags.Usage()
+		return exitError{2}
+	}
+
+	server, err := mock.New(oapi)
+	if err != nil {
+		return err
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL)
+		server.ServeHTTP(w, r)
+	})
+	log.Printf("serving mock API on http://%s", *addr)
+	return http.ListenAndServe(*addr, handler)
+}
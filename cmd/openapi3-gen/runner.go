@@ -1,12 +1,25 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/pkg/errors"
+	"github.com/skygeario/openapi3-gen/pkg/analysis"
+	"github.com/skygeario/openapi3-gen/pkg/codegen"
+	"github.com/skygeario/openapi3-gen/pkg/docs"
+	"github.com/skygeario/openapi3-gen/pkg/examples"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/overlay"
+	"github.com/skygeario/openapi3-gen/pkg/postman"
 	"github.com/skygeario/openapi3-gen/pkg/processor"
 	"github.com/skygeario/openapi3-gen/pkg/scanner"
+	"github.com/skygeario/openapi3-gen/pkg/split"
+	"github.com/skygeario/openapi3-gen/pkg/swagger2"
 	"gopkg.in/yaml.v2"
 )
 
@@ -22,30 +35,256 @@ func (err runnerError) Error() string {
 	return strings.Join(lines, "\n")
 }
 
-func run(baseDir string, patterns []string, outputFile string) error {
-	psr := processor.New()
-	scn := scanner.New(psr.Process)
+type runOptions struct {
+	// Format is the output format: yaml (default), swagger2, typescript,
+	// html, markdown or postman.
+	Format string
 
-	err := scn.Scan(baseDir, patterns)
+	// Templates is the directory of templates replacing the built-in
+	// templates of the html and markdown formats.
+	Templates string
+
+	// Split writes the output as multiple files in the output directory:
+	// yaml output is written as a file of each path item and component, and
+	// markdown output as a file of each tag.
+	Split bool
+
+	// InferParams is the parameter inference mode: empty to disable,
+	// "warn" to report parameters, or "add" to add undeclared parameters.
+	InferParams string
+
+	// OpenAPIVersion is the OpenAPI version of the generated document: 3.0
+	// (default) or 3.1.
+	OpenAPIVersion string
+
+	// Validate validates the generated document against the OpenAPI 3.0
+	// meta-schema. OpenAPI 3.1 documents are not validated.
+	Validate bool
+
+	// Base is the base document file to merge the generated document into.
+	Base string
+	// MergeStrategy resolves conflicting definitions in the base document.
+	MergeStrategy openapi3.MergeStrategy
+
+	// Overlays are the overlay document files to apply, in order.
+	Overlays []string
+
+	// SynthesizeExamples adds examples generated from schemas to media types
+	// without examples.
+	SynthesizeExamples bool
+
+	// Check compares the output with the existing output file instead of
+	// writing it.
+	Check bool
+}
+
+func run(baseDir string, patterns []string, outputFile string, opts runOptions) error {
+	oapi, err := generate(baseDir, patterns, opts)
 	if err != nil {
 		return err
 	}
 
-	oapi, errs := psr.End()
-	if len(errs) > 0 {
-		return runnerError{errs}
+	if opts.Format == "html" || opts.Split {
+		if outputFile == "" {
+			return fmt.Errorf("output format %v requires -output directory", opts.Format)
+		}
+		if opts.Check {
+			return fmt.Errorf("-check does not support output format %v", opts.Format)
+		}
+		var files map[string][]byte
+		switch opts.Format {
+		case "", "yaml":
+			files, err = split.Files(oapi)
+		case "html":
+			files, err = docs.RenderHTML(oapi, opts.Templates)
+		case "markdown":
+			files, err = docs.RenderMarkdownTags(oapi, opts.Templates)
+		default:
+			return fmt.Errorf("-split does not support output format %v", opts.Format)
+		}
+		if err != nil {
+			return err
+		}
+		return writeFiles(outputFile, files)
 	}
 
-	oapiData, err := yaml.Marshal(oapi)
+	var data []byte
+	switch opts.Format {
+	case "", "yaml":
+		data, err = yaml.Marshal(oapi)
+	case "swagger2":
+		doc, warnings := swagger2.Convert(oapi)
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", warning)
+		}
+		data, err = yaml.Marshal(doc)
+	case "typescript":
+		if openapi3.IsVersion31(oapi.Version) {
+			return fmt.Errorf("output format %v does not support OpenAPI 3.1", opts.Format)
+		}
+		data, err = codegen.GenerateTypeScript(oapi)
+	case "markdown":
+		data, err = docs.RenderMarkdown(oapi, opts.Templates)
+	case "postman":
+		collection, warnings := postman.Convert(oapi)
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", warning)
+		}
+		data, err = json.MarshalIndent(collection, "", "  ")
+	default:
+		return fmt.Errorf("unknown output format: %v", opts.Format)
+	}
 	if err != nil {
 		return err
 	}
 
+	if opts.Check {
+		return checkOutput(outputFile, data, opts.Format)
+	}
+	return writeOutput(outputFile, data)
+}
+
+// writeOutput writes data to the output file, or stdout if not specified.
+func writeOutput(outputFile string, data []byte) (err error) {
 	if outputFile != "" {
-		err = ioutil.WriteFile(outputFile, oapiData, 0644)
+		err = ioutil.WriteFile(outputFile, data, 0644)
 	} else {
-		_, err = fmt.Print(string(oapiData))
+		_, err = os.Stdout.Write(data)
+	}
+	return
+}
+
+// writeFiles writes the files to the output directory, by relative path.
+func writeFiles(outputDir string, files map[string][]byte) error {
+	for _, name := range openapi3.SortedKeys(files) {
+		file := filepath.Join(outputDir, filepath.FromSlash(name))
+		if rel, err := filepath.Rel(outputDir, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("file %s is outside of the output directory", name)
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file, files[name], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generate scans the packages and processes their annotations.
+func generate(baseDir string, patterns []string, opts runOptions) (*openapi3.OpenAPIObject, error) {
+	version := openapi3.Version30
+	if opts.OpenAPIVersion != "" {
+		var err error
+		version, err = openapi3.ParseVersion(opts.OpenAPIVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	psr := processor.NewForVersion(version)
+	scn := scanner.New(psr.Process)
+
+	switch opts.InferParams {
+	case "", "warn", "add":
+		break
+	default:
+		return nil, fmt.Errorf("unknown parameter inference mode: %v", opts.InferParams)
+	}
+
+	// Packages are type-checked for parameter inference and discriminators.
+	var pkgs []*scanner.Package
+	scn.HandlePackages(func(pkg *scanner.Package) {
+		pkgs = append(pkgs, pkg)
+	})
+
+	err := scn.Scan(baseDir, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	oapi, errs := psr.End()
+	if len(errs) > 0 {
+		return nil, runnerError{errs}
+	}
+
+	if opts.InferParams != "" {
+		for _, pkg := range pkgs {
+			diagnostics := analysis.InferParameters(oapi, pkg, psr.Operations(), opts.InferParams == "add")
+			for _, diagnostic := range diagnostics {
+				fmt.Fprintln(os.Stderr, diagnostic)
+			}
+		}
+	}
+
+	if discriminators := psr.Discriminators(); len(discriminators) > 0 {
+		diagnostics := analysis.ResolveDiscriminators(oapi, pkgs, psr.Schemas(), discriminators)
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(os.Stderr, diagnostic)
+		}
+	}
+
+	if opts.Base != "" {
+		oapi, err = mergeBase(opts.Base, oapi, opts.MergeStrategy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, overlayFile := range opts.Overlays {
+		o, err := overlay.LoadFile(overlayFile)
+		if err != nil {
+			return nil, err
+		}
+		oapi, err = o.Apply(oapi)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to apply overlay %s", overlayFile)
+		}
+	}
+
+	if opts.SynthesizeExamples {
+		examples.Synthesize(oapi)
 	}
 
-	return err
+	if opts.Validate && openapi3.IsVersion31(oapi.Version) {
+		fmt.Fprintf(os.Stderr, "warning: validation of OpenAPI %v documents is not supported, and is skipped\n", oapi.Version)
+	} else if opts.Validate {
+		errs, err := validateDocument(oapi, psr.SourceMap())
+		if err != nil {
+			return nil, err
+		}
+		if len(errs) > 0 {
+			return nil, runnerError{errs}
+		}
+	}
+
+	return oapi, nil
+}
+
+// mergeBase merges the generated document into the base document file.
+func mergeBase(baseFile string, oapi *openapi3.OpenAPIObject, strategy openapi3.MergeStrategy) (*openapi3.OpenAPIObject, error) {
+	if strategy == "" {
+		strategy = openapi3.MergeStrategyError
+	}
+	if !strategy.Validate() {
+		return nil, fmt.Errorf("unknown merge strategy: %v", strategy)
+	}
+
+	data, err := ioutil.ReadFile(baseFile)
+	if err != nil {
+		return nil, err
+	}
+	base, err := openapi3.Load(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load %s", baseFile)
+	}
+	if openapi3.IsVersion31(base.Version) != openapi3.IsVersion31(oapi.Version) {
+		return nil, fmt.Errorf("OpenAPI version of %s (%v) does not match generated specification (%v)", baseFile, base.Version, oapi.Version)
+	}
+
+	err = base.Merge(oapi, strategy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to merge into %s", baseFile)
+	}
+	return base, nil
 }
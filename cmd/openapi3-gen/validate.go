@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	"github.com/skygeario/openapi3-gen/pkg/validation"
+)
+
+// documentError is a validation violation, located at the source position
+// of the annotation producing the invalid object if known.
+type documentError struct {
+	jsonschema.ValidationError
+	sourceMap processor.SourceMap
+}
+
+func (err documentError) Error() string {
+	if source, ok := err.sourceMap.Lookup(err.Pointer); ok {
+		return fmt.Sprintf("%v: %v", source.Position, err.ValidationError)
+	}
+	return err.ValidationError.Error()
+}
+
+// validateDocument validates the generated document against the OpenAPI 3.0
+// meta-schema.
+func validateDocument(oapi *openapi3.OpenAPIObject, sourceMap processor.SourceMap) ([]error, error) {
+	doc, err := openapi3.ToDocument(oapi)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := validation.ValidateDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, violation := range violations {
+		errs = append(errs, documentError{violation, sourceMap})
+	}
+	return errs, nil
+}
+
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s validate <file>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitError{2}
+	}
+
+	data, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	doc, err := openapi3.ReadDocument(data)
+	if err != nil {
+		return err
+	}
+	violations, err := validation.ValidateDocument(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, violation := range violations {
+		fmt.Fprintf(os.Stdout, "%s: %v\n", flags.Arg(0), violation)
+	}
+	if len(violations) > 0 {
+		return exitError{1}
+	}
+	return nil
+}
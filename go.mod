@@ -1,6 +1,6 @@
 module github.com/skygeario/openapi3-gen
 
-go 1.12
+go 1.16
 
 require (
 	github.com/pkg/errors v0.8.1
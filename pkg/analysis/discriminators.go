@@ -0,0 +1,237 @@
+package analysis
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	"github.com/skygeario/openapi3-gen/pkg/scanner"
+)
+
+// declaredSchema is a component schema attached to a Go named type.
+type declaredSchema struct {
+	decl  processor.SchemaDeclaration
+	named *types.Named
+	pkg   *scanner.Package
+}
+
+// ResolveDiscriminators completes the polymorphic component schemas declared
+// on Go interface types. Each is a oneOf of the component schemas declared on
+// the named types implementing the interface, with a discriminator mapping
+// from the discriminator values of the types (the component ID by default).
+//
+// Component schemas of struct types embedding struct types with component
+// schemas are composed with allOf of the embedded schemas.
+func ResolveDiscriminators(
+	oapi *openapi3.OpenAPIObject,
+	pkgs []*scanner.Package,
+	schemas []processor.SchemaDeclaration,
+	discriminators []processor.DiscriminatorDeclaration,
+) []Diagnostic {
+	pkgsByFile := map[string]*scanner.Package{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			pkgsByFile[pkg.Fset.Position(file.Pos()).Filename] = pkg
+		}
+	}
+	lookup := func(name string, filename string) (types.Object, *scanner.Package) {
+		pkg, ok := pkgsByFile[filename]
+		if !ok {
+			return nil, nil
+		}
+		return pkg.Types.Scope().Lookup(name), pkg
+	}
+
+	var diagnostics []Diagnostic
+	report := func(position token.Position, id string, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Position: position,
+			Schema:   id,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	var declared []declaredSchema
+	schemaIDs := map[*types.TypeName]string{}
+	for _, decl := range schemas {
+		obj, pkg := lookup(decl.Name, decl.Position.Filename)
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			continue
+		}
+		named, ok := typeName.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+		declared = append(declared, declaredSchema{decl: decl, named: named, pkg: pkg})
+		schemaIDs[typeName] = decl.ID
+	}
+	sort.SliceStable(declared, func(i, j int) bool {
+		return declared[i].decl.ID < declared[j].decl.ID
+	})
+
+	for _, schema := range declared {
+		composeEmbeddedSchemas(oapi, schema, schemaIDs)
+	}
+
+	for _, decl := range discriminators {
+		obj, _ := lookup(decl.Name, decl.Position.Filename)
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			report(decl.Position, decl.ID, "Discriminator must be used on an interface type")
+			continue
+		}
+		iface, ok := typeName.Type().Underlying().(*types.Interface)
+		if !ok {
+			report(decl.Position, decl.ID, "Discriminator must be used on an interface type")
+			continue
+		}
+
+		var oneOf []interface{}
+		mapping := map[string]interface{}{}
+		mappedIDs := map[string]string{}
+		for _, schema := range declared {
+			if schema.named.Obj() == typeName || types.IsInterface(schema.named) {
+				continue
+			}
+			if !types.Implements(schema.named, iface) && !types.Implements(types.NewPointer(schema.named), iface) {
+				continue
+			}
+
+			id := schema.decl.ID
+			ref := map[string]interface{}(openapi3.MakeSchemaRef(id))
+			value := discriminatorValue(schema)
+			if existing, exists := mappedIDs[value]; exists {
+				report(schema.decl.Position, id, "duplicated discriminator value %q of %s", value, existing)
+				continue
+			}
+			mappedIDs[value] = id
+			mapping[value] = ref["$ref"]
+			oneOf = append(oneOf, ref)
+
+			if !hasProperty(oapi, id, decl.Property) {
+				report(schema.decl.Position, id, "schema has no discriminator property %q of %s", decl.Property, decl.ID)
+			}
+		}
+
+		if len(oneOf) == 0 {
+			report(decl.Position, decl.ID, "no types with component schemas implement %s", decl.Name)
+			continue
+		}
+
+		schema, _ := openapi3.SchemaMap(oapi.Components.Schemas[decl.ID])
+		if schema == nil {
+			continue
+		}
+		schema["oneOf"] = oneOf
+		schema["discriminator"] = map[string]interface{}{
+			"propertyName": decl.Property,
+			"mapping":      mapping,
+		}
+	}
+
+	return diagnostics
+}
+
+// discriminatorValue returns the discriminator value of the schema: the
+// value of the constant named by the declared discriminator value, the
+// declared value, or the component ID.
+func discriminatorValue(schema declaredSchema) string {
+	value := schema.decl.DiscriminatorValue
+	if value == "" {
+		return schema.decl.ID
+	}
+	if c, ok := schema.pkg.Types.Scope().Lookup(value).(*types.Const); ok && c.Val().Kind() == constant.String {
+		return constant.StringVal(c.Val())
+	}
+	return value
+}
+
+// composeEmbeddedSchemas composes the schema of a struct type with allOf of
+// the schemas of its embedded struct types.
+func composeEmbeddedSchemas(oapi *openapi3.OpenAPIObject, schema declaredSchema, schemaIDs map[*types.TypeName]string) {
+	st, ok := schema.named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	var bases []interface{}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Embedded() {
+			continue
+		}
+		t := field.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		named, ok := t.(*types.Named)
+		if !ok {
+			continue
+		}
+		if id, ok := schemaIDs[named.Obj()]; ok {
+			bases = append(bases, map[string]interface{}(openapi3.MakeSchemaRef(id)))
+		}
+	}
+	if len(bases) == 0 {
+		return
+	}
+
+	component := oapi.Components.Schemas[schema.decl.ID]
+	own, ok := openapi3.SchemaMap(component)
+	if !ok {
+		return
+	}
+	if _, composed := own["allOf"]; composed {
+		return
+	}
+	var composed openapi3.Schema = map[string]interface{}{
+		"allOf": append(bases, own),
+	}
+	*component = composed
+}
+
+// hasProperty returns whether the component schema, or a schema it is
+// composed of, declares the property. Schemas without properties are assumed
+// to declare it.
+func hasProperty(oapi *openapi3.OpenAPIObject, id string, property string) bool {
+	visited := map[string]bool{}
+	var check func(schema interface{}) (found bool, known bool)
+	check = func(schema interface{}) (bool, bool) {
+		resolved, refID := oapi.ResolveSchema(schema)
+		if refID != "" {
+			if visited[refID] {
+				return false, true
+			}
+			visited[refID] = true
+		}
+		if resolved == nil {
+			return false, false
+		}
+
+		known := false
+		if properties, ok := resolved["properties"].(map[string]interface{}); ok {
+			if _, exists := properties[property]; exists {
+				return true, true
+			}
+			known = true
+		}
+		if allOf, ok := resolved["allOf"].([]interface{}); ok {
+			for _, sub := range allOf {
+				found, subKnown := check(sub)
+				if found {
+					return true, true
+				}
+				known = known || subKnown
+			}
+		}
+		return false, known
+	}
+
+	found, known := check(openapi3.MakeSchemaRef(id))
+	return found || !known
+}
@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/scanner"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResolveDiscriminators(t *testing.T) {
+	src := `
+		package main
+
+		const EventTypeUserCreated = "user.created"
+
+		/*
+			@Discriminator type
+				Event payload
+		*/
+		type Event interface {
+			EventType() string
+		}
+
+		/*
+			@JSONSchema
+				{
+					"$id": "#BaseEvent",
+					"type": "object",
+					"properties": { "type": { "type": "string" } }
+				}
+		*/
+		type BaseEvent struct {
+			Type string
+		}
+
+		/*
+			@DiscriminatorValue EventTypeUserCreated
+			@JSONSchema
+				{
+					"$id": "#UserCreatedEvent",
+					"type": "object",
+					"properties": { "user_id": { "type": "string" } }
+				}
+		*/
+		type UserCreatedEvent struct {
+			BaseEvent
+			UserID string
+		}
+
+		func (UserCreatedEvent) EventType() string { return EventTypeUserCreated }
+
+		/*
+			@DiscriminatorValue payment.failed
+			@JSONSchema
+				{
+					"$id": "#PaymentFailedEvent",
+					"type": "object",
+					"properties": { "payment_id": { "type": "string" } }
+				}
+		*/
+		type PaymentFailedEvent struct {
+			PaymentID string
+		}
+
+		func (*PaymentFailedEvent) EventType() string { return "payment.failed" }
+
+		/*
+			@Discriminator kind
+		*/
+		type Unimplemented interface {
+			Kind() string
+		}
+	`
+
+	Convey("ResolveDiscriminators", t, func() {
+		pkg, psr := checkPackage(src)
+		oapi, errs := psr.End()
+		So(errs, ShouldBeEmpty)
+
+		diagnostics := ResolveDiscriminators(oapi, []*scanner.Package{pkg}, psr.Schemas(), psr.Discriminators())
+
+		var messages []string
+		for _, diagnostic := range diagnostics {
+			messages = append(messages, diagnostic.String())
+		}
+		So(messages, ShouldResemble, []string{
+			`handlers.go:51:3: schema PaymentFailedEvent: schema has no discriminator property "type" of Event`,
+			"handlers.go:60:3: schema Unimplemented: no types with component schemas implement Unimplemented",
+		})
+
+		So(*oapi.Components.Schemas["Event"], ShouldResemble, map[string]interface{}{
+			"description": "Event payload",
+			"oneOf": []interface{}{
+				map[string]interface{}{"$ref": "#/components/schemas/PaymentFailedEvent"},
+				map[string]interface{}{"$ref": "#/components/schemas/UserCreatedEvent"},
+			},
+			"discriminator": map[string]interface{}{
+				"propertyName": "type",
+				"mapping": map[string]interface{}{
+					"payment.failed": "#/components/schemas/PaymentFailedEvent",
+					"user.created":   "#/components/schemas/UserCreatedEvent",
+				},
+			},
+		})
+
+		So(*oapi.Components.Schemas["UserCreatedEvent"], ShouldResemble, map[string]interface{}{
+			"allOf": []interface{}{
+				map[string]interface{}(openapi3.MakeSchemaRef("BaseEvent")),
+				map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"user_id": map[string]interface{}{"type": "string"}},
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,226 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"net/http"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	"github.com/skygeario/openapi3-gen/pkg/scanner"
+)
+
+// Diagnostic is a message about an operation or a component schema reported
+// by an analysis pass.
+type Diagnostic struct {
+	Position token.Position
+	// Operation is the method and path of the operation, if the diagnostic
+	// is about an operation.
+	Operation string
+	// Schema is the component ID of the schema, if the diagnostic is about a
+	// component schema.
+	Schema  string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	subject := d.Operation
+	if d.Schema != "" {
+		subject = "schema " + d.Schema
+	}
+	return fmt.Sprintf("%v: %s: %s", d.Position, subject, d.Message)
+}
+
+// parameterRead is a read of a request parameter in a handler body.
+type parameterRead struct {
+	name     string
+	location openapi3.ParameterLocation
+	position token.Position
+}
+
+func (r parameterRead) key() string {
+	return parameterKey(r.name, r.location)
+}
+
+func parameterKey(name string, location openapi3.ParameterLocation) string {
+	if location == openapi3.ParameterLocationHeader {
+		name = http.CanonicalHeaderKey(name)
+	}
+	return string(location) + ":" + name
+}
+
+// InferParameters detects query, header and cookie parameters read by the
+// bodies of annotated handlers, and compares them with the declared
+// parameters of their operations. If add is true, undeclared parameters are
+// added to the operations as string parameters.
+func InferParameters(
+	oapi *openapi3.OpenAPIObject,
+	pkg *scanner.Package,
+	operations []processor.OperationDeclaration,
+	add bool,
+) []Diagnostic {
+	declsByPos := map[token.Position][]processor.OperationDeclaration{}
+	for _, op := range operations {
+		declsByPos[op.Position] = append(declsByPos[op.Position], op)
+	}
+
+	var diagnostics []Diagnostic
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			opDecls := declsByPos[pkg.Fset.Position(funcDecl.Pos())]
+			if len(opDecls) == 0 {
+				continue
+			}
+
+			reads := findParameterReads(pkg, funcDecl.Body)
+			for _, opDecl := range opDecls {
+				diagnostics = append(diagnostics, checkParameters(oapi, opDecl, reads, add)...)
+			}
+		}
+	}
+	return diagnostics
+}
+
+func checkParameters(
+	oapi *openapi3.OpenAPIObject,
+	opDecl processor.OperationDeclaration,
+	reads []parameterRead,
+	add bool,
+) (diagnostics []Diagnostic) {
+	opName := opDecl.Method + " " + opDecl.Path
+	report := func(position token.Position, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Position:  position,
+			Operation: opName,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	declared := map[string]*openapi3.ParameterObject{}
+	for _, param := range opDecl.Operation.Parameters {
+		paramObj := oapi.ResolveParameter(param)
+		if paramObj != nil {
+			declared[parameterKey(paramObj.Name, paramObj.Location)] = paramObj
+		}
+	}
+
+	read := map[string]bool{}
+	for _, r := range reads {
+		if read[r.key()] {
+			continue
+		}
+		read[r.key()] = true
+		if declared[r.key()] != nil {
+			continue
+		}
+
+		if add {
+			param := openapi3.NewParameterObject()
+			param.Name = r.name
+			param.Location = r.location
+			param.Schema = openapi3.Schema(map[string]interface{}{"type": "string"})
+			opDecl.Operation.Parameters = append(opDecl.Operation.Parameters, param)
+			report(r.position, "added undeclared %s parameter %q", r.location, r.name)
+		} else {
+			report(r.position, "%s parameter %q is read but not declared", r.location, r.name)
+		}
+	}
+
+	for _, param := range opDecl.Operation.Parameters {
+		paramObj := oapi.ResolveParameter(param)
+		if paramObj == nil || paramObj.Location == openapi3.ParameterLocationPath {
+			continue
+		}
+		if !read[parameterKey(paramObj.Name, paramObj.Location)] {
+			report(opDecl.Position, "%s parameter %q is declared but never read", paramObj.Location, paramObj.Name)
+		}
+	}
+
+	return
+}
+
+func findParameterReads(pkg *scanner.Package, body *ast.BlockStmt) []parameterRead {
+	var reads []parameterRead
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch expr := n.(type) {
+		case *ast.CallExpr:
+			sel, ok := expr.Fun.(*ast.SelectorExpr)
+			if !ok || len(expr.Args) == 0 {
+				return true
+			}
+			location, ok := parameterMethodLocation(pkg.Info.Selections[sel])
+			if !ok {
+				return true
+			}
+			if name, ok := constantString(pkg.Info, expr.Args[0]); ok {
+				reads = append(reads, parameterRead{
+					name:     name,
+					location: location,
+					position: pkg.Fset.Position(expr.Pos()),
+				})
+			}
+
+		case *ast.IndexExpr:
+			// e.g. r.URL.Query()["limit"]
+			if !isNamedType(pkg.Info.TypeOf(expr.X), "net/url", "Values") {
+				return true
+			}
+			if name, ok := constantString(pkg.Info, expr.Index); ok {
+				reads = append(reads, parameterRead{
+					name:     name,
+					location: openapi3.ParameterLocationQuery,
+					position: pkg.Fset.Position(expr.Pos()),
+				})
+			}
+		}
+		return true
+	})
+	return reads
+}
+
+func parameterMethodLocation(selection *types.Selection) (openapi3.ParameterLocation, bool) {
+	if selection == nil || selection.Kind() != types.MethodVal {
+		return "", false
+	}
+
+	method := selection.Obj().Name()
+	recv := selection.Recv()
+	switch {
+	case isNamedType(recv, "net/url", "Values") && method == "Get":
+		return openapi3.ParameterLocationQuery, true
+	case isNamedType(recv, "net/http", "Header") && (method == "Get" || method == "Values"):
+		return openapi3.ParameterLocationHeader, true
+	case isNamedType(recv, "net/http", "Request") && method == "FormValue":
+		return openapi3.ParameterLocationQuery, true
+	case isNamedType(recv, "net/http", "Request") && method == "Cookie":
+		return openapi3.ParameterLocationCookie, true
+	}
+	return "", false
+}
+
+func isNamedType(t types.Type, pkgPath string, name string) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+func constantString(info *types.Info, expr ast.Expr) (string, bool) {
+	value := info.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(value), true
+}
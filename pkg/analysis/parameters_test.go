@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	"github.com/skygeario/openapi3-gen/pkg/scanner"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func checkPackage(src string) (*scanner.Package, *processor.Processor) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+	So(err, ShouldBeNil)
+
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+	config := types.Config{Importer: importer.Default()}
+	typesPkg, err := config.Check("test", fset, []*ast.File{file}, info)
+	So(err, ShouldBeNil)
+
+	psr := processor.New()
+	psr.Process(fset, file)
+
+	return &scanner.Package{
+		Fset:  fset,
+		Path:  "test",
+		Files: []*ast.File{file},
+		Types: typesPkg,
+		Info:  info,
+	}, psr
+}
+
+func TestInferParameters(t *testing.T) {
+	src := `
+		package main
+
+		import "net/http"
+
+		const limitParam = "limit"
+
+		/*
+			@Operation GET /users - List Users
+				@Parameter q query
+				@Parameter {PageToken}
+				@Parameter X-Debug header
+		*/
+		func ListUsers(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			_ = query.Get(limitParam)
+			_ = query["offset"]
+			_ = r.Header.Get("x-request-id")
+			_ = r.FormValue("q")
+			_, _ = r.Cookie("session")
+		}
+
+		func Unannotated(w http.ResponseWriter, r *http.Request) {
+			_ = r.FormValue("unknown")
+		}
+	`
+
+	Convey("InferParameters", t, func() {
+		pkg, psr := checkPackage(src)
+		oapi, errs := psr.End()
+		So(errs, ShouldBeEmpty)
+
+		pageToken := openapi3.NewParameterObject()
+		pageToken.Name = "page_token"
+		pageToken.Location = openapi3.ParameterLocationQuery
+		oapi.Components.Parameters["PageToken"] = pageToken
+
+		Convey("should report undeclared and unread parameters", func() {
+			diagnostics := InferParameters(oapi, pkg, psr.Operations(), false)
+
+			var messages []string
+			for _, d := range diagnostics {
+				So(d.Operation, ShouldEqual, "GET /users")
+				messages = append(messages, d.Message)
+			}
+			So(messages, ShouldResemble, []string{
+				`query parameter "limit" is read but not declared`,
+				`query parameter "offset" is read but not declared`,
+				`header parameter "x-request-id" is read but not declared`,
+				`cookie parameter "session" is read but not declared`,
+				`query parameter "page_token" is declared but never read`,
+				`header parameter "X-Debug" is declared but never read`,
+			})
+			So(diagnostics[0].Position.Line, ShouldEqual, 16)
+			So(len(oapi.Paths["/users"].Get.Parameters), ShouldEqual, 3)
+		})
+
+		Convey("should add undeclared parameters", func() {
+			diagnostics := InferParameters(oapi, pkg, psr.Operations(), true)
+			So(len(diagnostics), ShouldEqual, 6)
+			So(diagnostics[0].Message, ShouldEqual, `added undeclared query parameter "limit"`)
+
+			params := oapi.Paths["/users"].Get.Parameters
+			So(len(params), ShouldEqual, 7)
+			So(params[6], ShouldResemble, &openapi3.ParameterObject{
+				Name:     "session",
+				Location: openapi3.ParameterLocationCookie,
+				Schema:   openapi3.Schema(map[string]interface{}{"type": "string"}),
+				Examples: map[string]openapi3.ExampleObject{},
+			})
+		})
+	})
+}
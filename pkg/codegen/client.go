@@ -0,0 +1,312 @@
+package codegen
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// GenerateClient generates a Go package with types of component schemas and
+// a Client with a method for each operation.
+func GenerateClient(oapi *openapi3.OpenAPIObject, config Config) ([]byte, error) {
+	f := newFile(config)
+	operations := listOperations(oapi)
+	types := newTypeGenerator(oapi, f.imports, reservedNames(operations,
+		"Client", "NewClient", "DefaultBaseURL"))
+	collectOperations(oapi, types, operations)
+	schemes := collectSecuritySchemes(oapi)
+
+	f.use("context", "io", "net/http", "net/url", "strings")
+	f.printf("%s\n", types.declarations())
+
+	if len(oapi.Servers) > 0 {
+		f.printf("// DefaultBaseURL is the URL of the first server in the specification.\n")
+		f.printf("const DefaultBaseURL = %s\n\n", strconv.Quote(serverURL(oapi.Servers[0])))
+	}
+
+	f.printf("// Client is a client of %s.\n", oapi.Info.Title)
+	f.printf("type Client struct {\n")
+	f.printf("\t// BaseURL is the URL of the server, without trailing slash.\n\tBaseURL string\n")
+	f.printf("\t// HTTPClient is used to send requests; http.DefaultClient is used if nil.\n\tHTTPClient *http.Client\n")
+	for _, scheme := range schemes {
+		f.printf("\n")
+		for _, field := range scheme.fields() {
+			f.printf("\t// %s is the %s of security scheme %s.\n", field.name, field.doc, strconv.Quote(scheme.name))
+			f.printf("\t%s string\n", field.name)
+		}
+	}
+	f.printf("}\n\n")
+
+	f.printf("// NewClient returns a client sending requests to the server at baseURL.\n")
+	f.printf("func NewClient(baseURL string) *Client {\n")
+	f.printf("\treturn &Client{BaseURL: strings.TrimSuffix(baseURL, \"/\")}\n}\n\n")
+
+	for _, op := range operations {
+		writeRequestType(f, op)
+		writeClientResponseType(f, op)
+		writeClientMethod(f, oapi, op)
+	}
+
+	writeClientSecurity(f, schemes)
+	f.printf("%s", clientRuntime)
+	return f.source(oapi)
+}
+
+var serverVariableFormat = regexp.MustCompile(`{([^}]+)}`)
+
+func serverURL(server openapi3.ServerObject) string {
+	url := serverVariableFormat.ReplaceAllStringFunc(server.URL, func(match string) string {
+		if variable, ok := server.Variables[match[1:len(match)-1]]; ok {
+			return variable.Default
+		}
+		return match
+	})
+	return strings.TrimSuffix(url, "/")
+}
+
+type securityScheme struct {
+	name   string
+	goName string
+	object *openapi3.SecuritySchemeObject
+}
+
+type credentialField struct {
+	name string
+	doc  string
+}
+
+func (s securityScheme) fields() []credentialField {
+	switch {
+	case s.object.Type == openapi3.SecuritySchemeTypeAPIKey:
+		return []credentialField{{s.goName, "API key"}}
+	case s.object.HTTPAuthScheme == "basic":
+		return []credentialField{{s.goName + "Username", "username"}, {s.goName + "Password", "password"}}
+	case s.object.HTTPAuthScheme == "bearer":
+		return []credentialField{{s.goName, "bearer token"}}
+	default:
+		return []credentialField{{s.goName, "credentials"}}
+	}
+}
+
+func collectSecuritySchemes(oapi *openapi3.OpenAPIObject) []securityScheme {
+	var names []string
+	for name := range oapi.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fieldNames := nameSet{"BaseURL": true, "HTTPClient": true}
+	var schemes []securityScheme
+	for _, name := range names {
+		schemes = append(schemes, securityScheme{
+			name:   name,
+			goName: fieldNames.allocate(goName(name)),
+			object: oapi.Components.SecuritySchemes[name],
+		})
+	}
+	return schemes
+}
+
+func writeClientResponseType(f *file, op operation) {
+	f.printf("// %sResponse is the response of %s.\n", op.Name, op.Name)
+	f.printf("type %sResponse struct {\n", op.Name)
+	f.printf("\tStatusCode int\n\tHTTPResponse *http.Response\n\t// Body is the raw response body.\n\tBody []byte\n")
+	for _, resp := range op.Responses {
+		if resp.Type == "" {
+			continue
+		}
+		f.printf("\t// JSON%s is the decoded body of response %s.\n", resp.CodeName, resp.StatusCode)
+		f.printf("\tJSON%s *%s\n", resp.CodeName, strings.TrimPrefix(resp.Type, "*"))
+	}
+	f.printf("}\n\n")
+}
+
+func writeClientMethod(f *file, oapi *openapi3.OpenAPIObject, op operation) {
+	f.printf("%s", operationDoc(op, "calls"))
+	f.printf("func (c *Client) %s(ctx context.Context, request %sRequest) (*%sResponse, error) {\n", op.Name, op.Name, op.Name)
+
+	path := strconv.Quote(op.Path)
+	for _, param := range op.Params {
+		if param.Location == openapi3.ParameterLocationPath {
+			f.use("fmt")
+			path = strings.Replace(path, "{"+param.Name+"}",
+				`" + url.PathEscape(fmt.Sprint(request.`+param.GoName+`)) + "`, -1)
+		}
+	}
+	f.printf("\tpath := %s\n", strings.Replace(path, ` + ""`, "", -1))
+
+	f.printf("\tquery := url.Values{}\n")
+	forEachParam(f, op, openapi3.ParameterLocationQuery, "query.Add(%s, fmt.Sprint(%s))\n")
+
+	f.printf("\tvar body io.Reader\n")
+	if op.Body != nil {
+		switch op.Body.FieldType() {
+		case "url.Values":
+			f.printf("\tif request.Body != nil {\n\t\tbody = strings.NewReader(request.Body.Encode())\n\t}\n")
+		case "io.Reader":
+			f.printf("\tbody = request.Body\n")
+		default:
+			f.use("bytes", "encoding/json")
+			f.printf("\tif request.Body != nil {\n")
+			f.printf("\t\tdata, err := json.Marshal(request.Body)\n\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n")
+			f.printf("\t\tbody = bytes.NewReader(data)\n\t}\n")
+		}
+	}
+
+	f.printf("\treq, err := c.newRequest(ctx, %s, path, query, body)\n", strconv.Quote(op.Method))
+	f.printf("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	if op.Body != nil && op.Body.MediaType != "" {
+		f.printf("\tif body != nil {\n\t\treq.Header.Set(\"Content-Type\", %s)\n\t}\n", strconv.Quote(op.Body.MediaType))
+	}
+	forEachParam(f, op, openapi3.ParameterLocationHeader, "req.Header.Add(%s, fmt.Sprint(%s))\n")
+	forEachParam(f, op, openapi3.ParameterLocationCookie, "req.AddCookie(&http.Cookie{Name: %s, Value: fmt.Sprint(%s)})\n")
+
+	// An empty list of the operation removes the top-level requirements.
+	security := op.Object.Security
+	if security == nil {
+		security = oapi.Security
+	}
+	if len(security) > 0 {
+		f.printf("\tc.authenticate(req, %#v)\n", securityAlternatives(security))
+	}
+
+	f.printf("\tresp, data, err := c.do(req)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	f.printf("\tresult := &%sResponse{StatusCode: resp.StatusCode, HTTPResponse: resp, Body: data}\n", op.Name)
+
+	var cases []string
+	var defaultCase string
+	for _, resp := range op.Responses {
+		if resp.Type == "" {
+			continue
+		}
+		f.use("encoding/json")
+		decode := "\t\tvar v " + strings.TrimPrefix(resp.Type, "*") + "\n" +
+			"\t\tif err := json.Unmarshal(data, &v); err != nil {\n\t\t\treturn result, err\n\t\t}\n" +
+			"\t\tresult.JSON" + resp.CodeName + " = &v\n"
+		switch {
+		case resp.StatusCode == "default":
+			defaultCase = "\tdefault:\n" + decode
+		case resp.Code() != 0:
+			cases = append(cases, "\tcase resp.StatusCode == "+resp.StatusCode+":\n"+decode)
+		default:
+			// e.g. 2XX
+			cases = append(cases, "\tcase resp.StatusCode/100 == "+resp.StatusCode[:1]+":\n"+decode)
+		}
+	}
+	if len(cases) > 0 || defaultCase != "" {
+		f.printf("\tswitch {\n%s%s\t}\n", strings.Join(cases, ""), defaultCase)
+	}
+	f.printf("\treturn result, nil\n}\n\n")
+}
+
+// forEachParam writes statements for each parameter in the location, with the
+// parameter name and value as format arguments.
+func forEachParam(f *file, op operation, location openapi3.ParameterLocation, format string) {
+	for _, param := range op.Params {
+		if param.Location != location {
+			continue
+		}
+		f.use("fmt")
+		name := strconv.Quote(param.Name)
+		field := "request." + param.GoName
+		switch {
+		case strings.HasPrefix(param.Type, "[]"):
+			f.printf("\tfor _, v := range %s {\n\t\t"+format+"\t}\n", field, name, "v")
+		case param.Required:
+			f.printf("\t"+format, name, field)
+		default:
+			f.printf("\tif %s != nil {\n\t\t"+format+"\t}\n", field, name, "*"+field)
+		}
+	}
+}
+
+func securityAlternatives(requirements openapi3.SecurityRequirements) [][]string {
+	var alternatives [][]string
+	for _, requirement := range requirements {
+		var schemes []string
+		for scheme := range requirement {
+			schemes = append(schemes, scheme)
+		}
+		sort.Strings(schemes)
+		alternatives = append(alternatives, schemes)
+	}
+	return alternatives
+}
+
+func writeClientSecurity(f *file, schemes []securityScheme) {
+	f.printf("// authenticate applies credentials of the first satisfied alternative of\n")
+	f.printf("// security requirements.\n")
+	f.printf("func (c *Client) authenticate(req *http.Request, alternatives [][]string) {\n")
+	f.printf("\tfor _, schemes := range alternatives {\n")
+	f.printf("\t\tsatisfied := true\n\t\tfor _, scheme := range schemes {\n")
+	f.printf("\t\t\tsatisfied = satisfied && c.hasCredentials(scheme)\n\t\t}\n")
+	f.printf("\t\tif !satisfied {\n\t\t\tcontinue\n\t\t}\n")
+	f.printf("\t\tfor _, scheme := range schemes {\n\t\t\tc.applyCredentials(req, scheme)\n\t\t}\n")
+	f.printf("\t\treturn\n\t}\n}\n\n")
+
+	f.printf("func (c *Client) hasCredentials(scheme string) bool {\n\tswitch scheme {\n")
+	for _, scheme := range schemes {
+		var conditions []string
+		for _, field := range scheme.fields() {
+			conditions = append(conditions, "c."+field.name+" != \"\"")
+		}
+		f.printf("\tcase %s:\n\t\treturn %s\n", strconv.Quote(scheme.name), strings.Join(conditions, " && "))
+	}
+	f.printf("\t}\n\treturn false\n}\n\n")
+
+	f.printf("func (c *Client) applyCredentials(req *http.Request, scheme string) {\n\tswitch scheme {\n")
+	for _, scheme := range schemes {
+		f.printf("\tcase %s:\n", strconv.Quote(scheme.name))
+		obj := scheme.object
+		name := strconv.Quote(obj.APIKeyName)
+		switch {
+		case obj.Type == openapi3.SecuritySchemeTypeAPIKey && obj.APIKeyLocation == openapi3.SecuritySchemeAPIKeyLocationQuery:
+			f.printf("\t\tquery := req.URL.Query()\n\t\tquery.Set(%s, c.%s)\n\t\treq.URL.RawQuery = query.Encode()\n", name, scheme.goName)
+		case obj.Type == openapi3.SecuritySchemeTypeAPIKey && obj.APIKeyLocation == openapi3.SecuritySchemeAPIKeyLocationCookie:
+			f.printf("\t\treq.AddCookie(&http.Cookie{Name: %s, Value: c.%s})\n", name, scheme.goName)
+		case obj.Type == openapi3.SecuritySchemeTypeAPIKey:
+			f.printf("\t\treq.Header.Set(%s, c.%s)\n", name, scheme.goName)
+		case obj.HTTPAuthScheme == "basic":
+			f.printf("\t\treq.SetBasicAuth(c.%sUsername, c.%sPassword)\n", scheme.goName, scheme.goName)
+		case obj.HTTPAuthScheme == "bearer":
+			f.printf("\t\treq.Header.Set(\"Authorization\", \"Bearer \"+c.%s)\n", scheme.goName)
+		default:
+			f.printf("\t\treq.Header.Set(\"Authorization\", %s+c.%s)\n", strconv.Quote(obj.HTTPAuthScheme+" "), scheme.goName)
+		}
+	}
+	f.printf("\t}\n}\n\n")
+}
+
+const clientRuntime = `func (c *Client) newRequest(ctx context.Context, method string, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	reqURL := c.BaseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+	return req.WithContext(ctx), nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, []byte, error) {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, data, nil
+}
+`
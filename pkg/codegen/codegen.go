@@ -0,0 +1,64 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+type Config struct {
+	// PackageName is the package name of generated code.
+	PackageName string
+}
+
+// file accumulates the source code of a generated file.
+type file struct {
+	config  Config
+	imports map[string]bool
+	body    bytes.Buffer
+}
+
+func newFile(config Config) *file {
+	return &file{
+		config:  config,
+		imports: map[string]bool{},
+	}
+}
+
+func (f *file) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&f.body, format, args...)
+}
+
+func (f *file) use(imports ...string) {
+	for _, path := range imports {
+		f.imports[path] = true
+	}
+}
+
+// source returns the formatted source code of the file.
+func (f *file) source(oapi *openapi3.OpenAPIObject) ([]byte, error) {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by openapi3-gen from %s. DO NOT EDIT.\n\n", strconv.Quote(oapi.Info.Title))
+	fmt.Fprintf(&src, "package %s\n\n", f.config.PackageName)
+
+	var imports []string
+	for path := range f.imports {
+		imports = append(imports, strconv.Quote(path))
+	}
+	sort.Strings(imports)
+	if len(imports) > 0 {
+		fmt.Fprintf(&src, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	src.Write(f.body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return formatted, nil
+}
@@ -0,0 +1,587 @@
+package codegen
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testAPISource = `
+	package main
+
+	/*
+		@API Pet Store
+		@Version 1.0.0
+		@SecuritySchemeAPIKey api_key header X-API-Key
+		@SecuritySchemeHTTP access_token Bearer JWT
+	*/
+	func main() {}
+
+	// @JSONSchema
+	const PetSchema = ` + "`" + `
+	{
+		"$id": "#Pet",
+		"type": "object",
+		"description": "A pet.",
+		"required": ["id", "kind"],
+		"properties": {
+			"id": { "type": "integer", "format": "int64" },
+			"kind": { "$ref": "#PetKind" },
+			"name": { "type": "string", "nullable": true },
+			"tags": { "type": "array", "items": { "type": "string" } },
+			"owner": {
+				"type": "object",
+				"properties": {
+					"user_id": { "type": "string" }
+				}
+			}
+		}
+	}
+	` + "`" + `
+
+	// @JSONSchema
+	const PetKindSchema = ` + "`" + `
+	{
+		"$id": "#PetKind",
+		"type": "string",
+		"enum": ["cat", "dog"]
+	}
+	` + "`" + `
+
+	// @JSONSchema
+	const DogSchema = ` + "`" + `
+	{
+		"$id": "#Dog",
+		"allOf": [
+			{ "$ref": "#Pet" },
+			{ "type": "object", "properties": { "barks": { "type": "boolean" } } }
+		]
+	}
+	` + "`" + `
+
+	/*
+		@Response ErrorResponse
+			Error.
+			@JSONSchema
+				{ "type": "object", "properties": { "message": { "type": "string" } } }
+	*/
+	type ErrorResponse struct{}
+
+	/*
+		@Operation GET /pets - List Pets
+			@Parameter limit query
+				@JSONSchema
+					{ "type": "integer", "format": "int32" }
+			@Parameter kind query
+				@JSONSchema
+					{ "type": "array", "items": { "type": "string" } }
+			@Parameter X-Request-ID header
+			@SecurityRequirement api_key
+			@Response 200
+				Pets.
+				@JSONSchema
+					{ "type": "array", "items": { "$ref": "#Pet" } }
+			@Response default {ErrorResponse}
+
+		@Operation POST /pets/{id} - Update Pet
+			@Parameter id path
+				@JSONSchema
+					{ "type": "integer" }
+			@RequestBody
+				@JSONSchema {Pet}
+			@SecurityRequirement access_token
+			@Response 204
+				Updated.
+			@Response default {ErrorResponse}
+	*/
+	func Pets() {}
+`
+
+func processTestAPI() *openapi3.OpenAPIObject {
+	psr := processor.New()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", testAPISource, parser.ParseComments)
+	So(err, ShouldBeNil)
+	psr.Process(fset, file)
+	oapi, errs := psr.End()
+	So(errs, ShouldBeEmpty)
+	oapi.Paths["/pets/{id}"].Post.RequestBody.(*openapi3.RequestBodyObject).Required = true
+	return oapi
+}
+
+// typeCheck type-checks generated source code, returning the package scope.
+func typeCheck(src []byte) *types.Scope {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", src, 0)
+	So(err, ShouldBeNil)
+
+	config := types.Config{Importer: importer.Default()}
+	pkg, err := config.Check("generated", fset, []*ast.File{file}, nil)
+	So(err, ShouldBeNil)
+	return pkg.Scope()
+}
+
+// runGenerated builds the generated code of package main with the main
+// function, and runs it with the arguments.
+func runGenerated(src []byte, mainSrc string, args ...string) string {
+	dir, err := ioutil.TempDir("", "openapi3-gen")
+	So(err, ShouldBeNil)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"go.mod":       "module generated\n\ngo 1.16\n",
+		"generated.go": string(src),
+		"main.go":      mainSrc,
+	}
+	for name, content := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		So(err, ShouldBeNil)
+	}
+
+	cmd := exec.Command("go", append([]string{"run", "."}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	So(string(out), ShouldNotContainSubstring, "error")
+	So(err, ShouldBeNil)
+	return string(out)
+}
+
+func TestGoName(t *testing.T) {
+	Convey("goName", t, func() {
+		So(goName("user_id"), ShouldEqual, "UserID")
+		So(goName("Get User"), ShouldEqual, "GetUser")
+		So(goName("X-Request-ID"), ShouldEqual, "XRequestID")
+		So(goName("petKind"), ShouldEqual, "PetKind")
+		So(goName("HTTPServer"), ShouldEqual, "HTTPServer")
+		So(goName("get /user/{id}"), ShouldEqual, "GetUserID")
+		So(goName("2fa"), ShouldEqual, "N2fa")
+		So(goName(""), ShouldEqual, "X")
+	})
+}
+
+func TestGenerateServer(t *testing.T) {
+	Convey("GenerateServer", t, func() {
+		oapi := processTestAPI()
+		src, err := GenerateServer(oapi, Config{PackageName: "petstore"})
+		So(err, ShouldBeNil)
+
+		scope := typeCheck(src)
+		for _, name := range []string{
+			"Pet", "PetKind", "PetKindCat", "PetKindDog", "PetOwner", "Dog", "ErrorResponse",
+			"ServerInterface", "NewRouter",
+			"ListPetsRequest", "ListPetsResponse", "ListPets200JSONResponse", "ListPetsDefaultJSONResponse",
+			"UpdatePetRequest", "UpdatePetResponse", "UpdatePet204Response", "UpdatePetDefaultJSONResponse",
+		} {
+			So(scope.Lookup(name), ShouldNotBeNil)
+		}
+
+		pet := scope.Lookup("Pet").Type().Underlying().(*types.Struct)
+		fields := map[string]string{}
+		for i := 0; i < pet.NumFields(); i++ {
+			fields[pet.Field(i).Name()] = pet.Field(i).Type().String() + " " + pet.Tag(i)
+		}
+		So(fields, ShouldResemble, map[string]string{
+			"ID":    `int64 json:"id"`,
+			"Kind":  `generated.PetKind json:"kind"`,
+			"Name":  `*string json:"name,omitempty"`,
+			"Tags":  `[]string json:"tags,omitempty"`,
+			"Owner": `*generated.PetOwner json:"owner,omitempty"`,
+		})
+
+		dog := scope.Lookup("Dog").Type().Underlying().(*types.Struct)
+		So(dog.Field(0).Embedded(), ShouldBeTrue)
+		So(dog.Field(0).Name(), ShouldEqual, "Pet")
+
+		req := scope.Lookup("ListPetsRequest").Type().Underlying().(*types.Struct)
+		So(req.NumFields(), ShouldEqual, 3)
+		So(req.Field(0).Type().String(), ShouldEqual, "*int32")
+		So(req.Field(1).Type().String(), ShouldEqual, "[]string")
+		So(req.Field(2).Name(), ShouldEqual, "XRequestID")
+
+		updateReq := scope.Lookup("UpdatePetRequest").Type().Underlying().(*types.Struct)
+		So(updateReq.Field(0).Type().String(), ShouldEqual, "int64")
+		So(updateReq.Field(1).Type().String(), ShouldEqual, "*generated.Pet")
+
+		iface := scope.Lookup("ServerInterface").Type().Underlying().(*types.Interface)
+		So(iface.NumMethods(), ShouldEqual, 2)
+		So(iface.Method(0).Name(), ShouldEqual, "ListPets")
+		So(iface.Method(1).Name(), ShouldEqual, "UpdatePet")
+
+		Convey("should not name schema types after operation types", func() {
+			var schema openapi3.Schema = map[string]interface{}{"type": "string"}
+			oapi.Components.Schemas["ListPetsRequest"] = &schema
+			src, err := GenerateServer(oapi, Config{PackageName: "petstore"})
+			So(err, ShouldBeNil)
+
+			scope := typeCheck(src)
+			So(scope.Lookup("ListPetsRequest").Type().Underlying(), ShouldHaveSameTypeAs, &types.Struct{})
+			So(scope.Lookup("ListPetsRequest2").Type().Underlying().String(), ShouldEqual, "string")
+		})
+
+		Convey("should route and decode requests", func() {
+			if _, err := exec.LookPath("go"); err != nil {
+				SkipSo("go command is not available")
+				return
+			}
+
+			// RenamePet has a required query parameter and a form body.
+			pathItem := oapi.Paths["/pets/{id}"]
+			pathItem.Put = &openapi3.OperationObject{
+				ID: "renamePet",
+				Parameters: append(pathItem.Post.Parameters, &openapi3.ParameterObject{
+					Name:     "version",
+					Location: openapi3.ParameterLocationQuery,
+					Required: true,
+					Schema:   map[string]interface{}{"type": "integer"},
+				}),
+				RequestBody: &openapi3.RequestBodyObject{
+					Content: map[string]openapi3.MediaTypeObject{"application/x-www-form-urlencoded": {}},
+				},
+				Responses: map[string]openapi3.Response{
+					"200": &openapi3.ResponseObject{Description: "Renamed."},
+				},
+			}
+			oapi.Paths["/pets/{id}"] = pathItem
+			src, err := GenerateServer(oapi, Config{PackageName: "main"})
+			So(err, ShouldBeNil)
+
+			out := runGenerated(src, `
+				package main
+
+				import (
+					"context"
+					"fmt"
+					"io/ioutil"
+					"net/http"
+					"net/http/httptest"
+					"strings"
+				)
+
+				type petServer struct{}
+
+				func (petServer) ListPets(ctx context.Context, req ListPetsRequest) (ListPetsResponse, error) {
+					return ListPets200JSONResponse{{ID: int64(*req.Limit), Kind: PetKindCat, Name: req.XRequestID, Tags: req.Kind}}, nil
+				}
+
+				func (petServer) RenamePet(ctx context.Context, req RenamePetRequest) (RenamePetResponse, error) {
+					return RenamePet200Response{
+						ContentType: "text/plain",
+						Body:        strings.NewReader(fmt.Sprintln(req.ID, req.Version, req.Body.Get("name"))),
+					}, nil
+				}
+
+				func (petServer) UpdatePet(ctx context.Context, req UpdatePetRequest) (UpdatePetResponse, error) {
+					message := fmt.Sprint(req.ID, " ", req.Body.Kind, " ", *req.Body.Name)
+					return UpdatePetDefaultJSONResponse{StatusCode: 200, Body: ErrorResponse{Message: &message}}, nil
+				}
+
+				func main() {
+					server := httptest.NewServer(NewRouter(petServer{}))
+					defer server.Close()
+
+					requests := []struct {
+						method      string
+						path        string
+						contentType string
+						body        string
+					}{
+						{"GET", "/pets?limit=10&kind=cat&kind=dog", "", ""},
+						{"GET", "/pets?limit=ten", "", ""},
+						{"POST", "/pets/42", "application/json", "{\"id\": 42, \"kind\": \"dog\", \"name\": \"Rex\"}"},
+						{"POST", "/pets/42", "application/json", "{"},
+						{"POST", "/pets/rex", "application/json", "{}"},
+						{"PUT", "/pets/42?version=3", "application/x-www-form-urlencoded", "name=Max"},
+						{"PUT", "/pets/42", "application/x-www-form-urlencoded", "name=Max"},
+						{"GET", "/pets/42/owner", "", ""},
+						{"DELETE", "/pets", "", ""},
+					}
+					for _, request := range requests {
+						req, err := http.NewRequest(request.method, server.URL+request.path, strings.NewReader(request.body))
+						if err != nil {
+							panic(err)
+						}
+						req.Header.Set("X-Request-ID", "req-1")
+						if request.contentType != "" {
+							req.Header.Set("Content-Type", request.contentType)
+						}
+						resp, err := http.DefaultClient.Do(req)
+						if err != nil {
+							panic(err)
+						}
+						body, err := ioutil.ReadAll(resp.Body)
+						resp.Body.Close()
+						if err != nil {
+							panic(err)
+						}
+						fmt.Println(resp.StatusCode, strings.TrimSpace(string(body)))
+					}
+				}
+			`)
+
+			So(strings.Split(strings.TrimSpace(out), "\n"), ShouldResemble, []string{
+				`200 [{"id":10,"kind":"cat","name":"req-1","tags":["cat","dog"]}]`,
+				`400 invalid parameter "limit": strconv.ParseInt: parsing "ten": invalid syntax`,
+				`200 {"message":"42 dog Rex"}`,
+				`400 invalid request body: unexpected EOF`,
+				`400 invalid parameter "id": strconv.ParseInt: parsing "rex": invalid syntax`,
+				`200 42 3 Max`,
+				`400 invalid parameter "version": missing required parameter`,
+				`404 404 page not found`,
+				`405 Method Not Allowed`,
+			})
+		})
+	})
+}
+
+func TestGenerateClient(t *testing.T) {
+	Convey("GenerateClient", t, func() {
+		oapi := processTestAPI()
+		oapi.Servers = []openapi3.ServerObject{{
+			URL: "https://{env}.example.com/",
+			Variables: map[string]openapi3.ServerVariable{
+				"env": {Default: "api"},
+			},
+		}}
+		src, err := GenerateClient(oapi, Config{PackageName: "petstore"})
+		So(err, ShouldBeNil)
+
+		scope := typeCheck(src)
+		So(scope.Lookup("DefaultBaseURL").(*types.Const).Val().String(), ShouldEqual, `"https://api.example.com"`)
+
+		client := scope.Lookup("Client").Type()
+		clientStruct := client.Underlying().(*types.Struct)
+		var fields []string
+		for i := 0; i < clientStruct.NumFields(); i++ {
+			fields = append(fields, clientStruct.Field(i).Name())
+		}
+		So(fields, ShouldResemble, []string{"BaseURL", "HTTPClient", "AccessToken", "APIKey"})
+
+		methods := types.NewMethodSet(types.NewPointer(client))
+		So(methods.Lookup(nil, "ListPets"), ShouldNotBeNil)
+		So(methods.Lookup(nil, "UpdatePet"), ShouldNotBeNil)
+
+		resp := scope.Lookup("ListPetsResponse").Type().Underlying().(*types.Struct)
+		respFields := map[string]string{}
+		for i := 0; i < resp.NumFields(); i++ {
+			respFields[resp.Field(i).Name()] = resp.Field(i).Type().String()
+		}
+		So(respFields, ShouldResemble, map[string]string{
+			"StatusCode":   "int",
+			"HTTPResponse": "*net/http.Response",
+			"Body":         "[]byte",
+			"JSON200":      "*[]generated.Pet",
+			"JSONDefault":  "*generated.ErrorResponse",
+		})
+
+		So(string(src), ShouldContainSubstring, "// ListPets calls GET /pets.\n//\n// List Pets\nfunc (c *Client) ListPets(")
+		So(string(src), ShouldNotContainSubstring, "io/ioutil")
+		So(string(src), ShouldContainSubstring, `path := "/pets/" + url.PathEscape(fmt.Sprint(request.ID))`)
+		So(string(src), ShouldContainSubstring, `c.authenticate(req, [][]string{[]string{"api_key"}})`)
+		So(string(src), ShouldContainSubstring, `req.Header.Set("Authorization", "Bearer "+c.AccessToken)`)
+		So(string(src), ShouldContainSubstring, `req.Header.Set("X-API-Key", c.APIKey)`)
+
+		Convey("should send requests and decode responses", func() {
+			if _, err := exec.LookPath("go"); err != nil {
+				SkipSo("go command is not available")
+				return
+			}
+
+			// UpdatePet has no security requirements, and inherits them.
+			oapi.Security = openapi3.SecurityRequirements{{"access_token": []string{}}}
+			oapi.Paths["/pets/{id}"].Post.Security = nil
+			src, err := GenerateClient(oapi, Config{PackageName: "main"})
+			So(err, ShouldBeNil)
+
+			type request struct {
+				Method        string
+				Path          string
+				Query         string
+				RequestID     string
+				APIKey        string
+				Authorization string
+				ContentType   string
+				Body          map[string]interface{}
+			}
+			var requests []request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req := request{
+					Method:        r.Method,
+					Path:          r.URL.Path,
+					Query:         r.URL.RawQuery,
+					RequestID:     r.Header.Get("X-Request-ID"),
+					APIKey:        r.Header.Get("X-API-Key"),
+					Authorization: r.Header.Get("Authorization"),
+					ContentType:   r.Header.Get("Content-Type"),
+				}
+				_ = json.NewDecoder(r.Body).Decode(&req.Body)
+				requests = append(requests, req)
+
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == "GET" {
+					_, _ = w.Write([]byte(`[{"id": 1, "kind": "cat", "name": "Tom"}]`))
+				} else {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"message": "invalid pet"}`))
+				}
+			}))
+			defer server.Close()
+
+			out := runGenerated(src, `
+				package main
+
+				import (
+					"context"
+					"fmt"
+					"os"
+				)
+
+				func main() {
+					c := NewClient(os.Args[1])
+					c.APIKey = "key"
+					c.AccessToken = "token"
+
+					limit := int32(10)
+					requestID := "req-1"
+					pets, err := c.ListPets(context.Background(), ListPetsRequest{
+						Limit:      &limit,
+						Kind:       []string{"cat", "dog"},
+						XRequestID: &requestID,
+					})
+					if err != nil {
+						panic(err)
+					}
+					pet := (*pets.JSON200)[0]
+					fmt.Println(pets.StatusCode, pet.ID, pet.Kind, *pet.Name)
+
+					name := "Rex"
+					updated, err := c.UpdatePet(context.Background(), UpdatePetRequest{
+						ID:   42,
+						Body: &Pet{ID: 42, Kind: PetKindDog, Name: &name},
+					})
+					if err != nil {
+						panic(err)
+					}
+					fmt.Println(updated.StatusCode, *updated.JSONDefault.Message)
+				}
+			`, server.URL)
+
+			So(strings.Split(strings.TrimSpace(out), "\n"), ShouldResemble, []string{
+				"200 1 cat Tom",
+				"400 invalid pet",
+			})
+			So(requests, ShouldResemble, []request{
+				{
+					Method:    "GET",
+					Path:      "/pets",
+					Query:     "kind=cat&kind=dog&limit=10",
+					RequestID: "req-1",
+					APIKey:    "key",
+				},
+				{
+					Method:        "POST",
+					Path:          "/pets/42",
+					Authorization: "Bearer token",
+					ContentType:   "application/json",
+					Body:          map[string]interface{}{"id": 42.0, "kind": "dog", "name": "Rex"},
+				},
+			})
+		})
+
+		Convey("should not authenticate operations without security requirements", func() {
+			oapi.Security = openapi3.SecurityRequirements{{"access_token": []string{}}}
+			oapi.Paths["/pets/{id}"].Post.Security = openapi3.SecurityRequirements{}
+			src, err := GenerateClient(oapi, Config{PackageName: "petstore"})
+			So(err, ShouldBeNil)
+			So(strings.Count(string(src), "c.authenticate(req, "), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	Convey("GenerateTypeScript", t, func() {
+		oapi := processTestAPI()
+		src, err := GenerateTypeScript(oapi)
+		So(err, ShouldBeNil)
+
+		ts := string(src)
+		So(ts, ShouldContainSubstring, `
+/** A pet. */
+export interface Pet {
+  id: number;
+  kind: PetKind;
+  name?: string | null;
+  owner?: {
+    user_id?: string;
+  };
+  tags?: string[];
+}
+`)
+		So(ts, ShouldContainSubstring, `
+export type PetKind = "cat" | "dog";
+`)
+		So(ts, ShouldContainSubstring, `
+export type Dog = Pet & {
+  barks?: boolean;
+};
+`)
+		So(ts, ShouldContainSubstring, `
+export interface Responses {
+  /** Error. */
+  ErrorResponse: {
+    message?: string;
+  };
+}
+`)
+		So(ts, ShouldContainSubstring, `
+  "/pets": {
+    /** List Pets */
+    get: {
+      parameters: {
+        query?: {
+          limit?: number;
+          kind?: string[];
+        };
+        header?: {
+          "X-Request-ID"?: unknown;
+        };
+      };
+      responses: {
+        /** Pets. */
+        200: Pet[];
+        default: Responses["ErrorResponse"];
+      };
+    };
+  };
+`)
+		So(ts, ShouldContainSubstring, `
+    post: {
+      parameters: {
+        path: {
+          id: number;
+        };
+      };
+      requestBody: Pet;
+      responses: {
+        /** Updated. */
+        204: void;
+        default: Responses["ErrorResponse"];
+      };
+    };
+`)
+	})
+}
@@ -0,0 +1,103 @@
+package codegen
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var initialisms = map[string]bool{
+	"API":  true,
+	"HTML": true,
+	"HTTP": true,
+	"ID":   true,
+	"JSON": true,
+	"JWT":  true,
+	"SQL":  true,
+	"URI":  true,
+	"URL":  true,
+	"UUID": true,
+	"XML":  true,
+}
+
+// goName converts an identifier in the specification to an exported Go
+// identifier, e.g. user_id -> UserID.
+func goName(str string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	runes := []rune(str)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(word) > 0:
+			prevLower := unicode.IsLower(word[len(word)-1]) || unicode.IsDigit(word[len(word)-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				flush()
+			}
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var name strings.Builder
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			name.WriteString(upper)
+			continue
+		}
+		wordRunes := []rune(word)
+		name.WriteRune(unicode.ToUpper(wordRunes[0]))
+		name.WriteString(string(wordRunes[1:]))
+	}
+
+	result := name.String()
+	if result == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		result = "N" + result
+	}
+	return result
+}
+
+// nameSet allocates unique Go identifiers.
+type nameSet map[string]bool
+
+func (names nameSet) allocate(name string) string {
+	if !names[name] {
+		names[name] = true
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		if !names[candidate] {
+			names[candidate] = true
+			return candidate
+		}
+	}
+}
+
+// docComment formats a text as Go comment lines.
+func docComment(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, strings.TrimRight("// "+line, " "))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,267 @@
+package codegen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+const jsonMediaType = "application/json"
+const formMediaType = "application/x-www-form-urlencoded"
+
+type operation struct {
+	Name      string
+	Method    string
+	Path      string
+	Object    *openapi3.OperationObject
+	Params    []parameter
+	Body      *requestBody
+	Responses []response
+}
+
+type parameter struct {
+	Name     string
+	GoName   string
+	Location openapi3.ParameterLocation
+	Required bool
+	// Type is the Go type of the parameter value: a scalar type, or a
+	// slice of scalar type.
+	Type string
+}
+
+type requestBody struct {
+	Required  bool
+	MediaType string
+	// Type is the Go type of JSON bodies; empty for other media types.
+	Type string
+}
+
+// FieldType returns the Go type of the request body field: a pointer for
+// JSON bodies, url.Values for form bodies, or io.Reader otherwise.
+func (b *requestBody) FieldType() string {
+	switch {
+	case b.Type != "":
+		return "*" + strings.TrimPrefix(b.Type, "*")
+	case b.MediaType == formMediaType:
+		return "url.Values"
+	default:
+		return "io.Reader"
+	}
+}
+
+type response struct {
+	StatusCode string
+	// CodeName is the status code in Go identifiers, e.g. 200, 2XX, Default.
+	CodeName    string
+	GoName      string
+	Description string
+	MediaType   string
+	// Type is the Go type of JSON bodies; empty for other media types.
+	Type string
+}
+
+// Code returns the HTTP status code, or 0 for default and range responses.
+func (r response) Code() int {
+	code := 0
+	for _, c := range r.StatusCode {
+		if c < '0' || c > '9' {
+			return 0
+		}
+		code = code*10 + int(c-'0')
+	}
+	return code
+}
+
+// listOperations returns the named operations in paths, sorted by path and
+// method.
+func listOperations(oapi *openapi3.OpenAPIObject) []operation {
+	var paths []string
+	for path := range oapi.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	opNames := nameSet{}
+	var operations []operation
+	for _, path := range paths {
+		pathItem := oapi.Paths[path]
+		for _, method := range openapi3.Methods {
+			opObj := pathItem.GetOperation(method)
+			if opObj == nil {
+				continue
+			}
+			operations = append(operations, operation{
+				Name:   opNames.allocate(operationName(method, path, opObj)),
+				Method: method,
+				Path:   path,
+				Object: opObj,
+			})
+		}
+	}
+	return operations
+}
+
+// reservedNames returns the names of types declared for the operations, and
+// other declarations of generated code, so that types of component schemas do
+// not conflict with them.
+func reservedNames(operations []operation, declarations ...string) []string {
+	names := declarations
+	for _, op := range operations {
+		names = append(names, op.Name+"Request", op.Name+"Response")
+		for code := range op.Object.Responses {
+			names = append(names,
+				op.Name+responseCodeName(code)+"Response",
+				op.Name+responseCodeName(code)+"JSONResponse",
+			)
+		}
+	}
+	return names
+}
+
+// collectOperations resolves parameters, request bodies and responses of
+// operations, declaring their types.
+func collectOperations(oapi *openapi3.OpenAPIObject, types *typeGenerator, operations []operation) {
+	for i := range operations {
+		op := &operations[i]
+		pathItem := oapi.Paths[op.Path]
+		op.Params = collectParameters(oapi, pathItem, op.Object)
+		op.Body = collectRequestBody(oapi, types, op.Name, op.Object.RequestBody)
+		op.Responses = collectResponses(oapi, types, op.Name, op.Object.Responses)
+	}
+}
+
+func operationName(method string, path string, op *openapi3.OperationObject) string {
+	if op.ID != "" {
+		return goName(op.ID)
+	}
+	if op.Summary != "" {
+		return goName(op.Summary)
+	}
+	return goName(strings.ToLower(method) + " " + path)
+}
+
+func collectParameters(oapi *openapi3.OpenAPIObject, pathItem openapi3.PathItemObject, op *openapi3.OperationObject) []parameter {
+	var paramObjs []*openapi3.ParameterObject
+	for i := range pathItem.Parameters {
+		paramObjs = append(paramObjs, &pathItem.Parameters[i])
+	}
+	for _, param := range op.Parameters {
+		if paramObj := oapi.ResolveParameter(param); paramObj != nil {
+			paramObjs = append(paramObjs, paramObj)
+		}
+	}
+
+	names := nameSet{"Body": true}
+	var params []parameter
+	for _, paramObj := range paramObjs {
+		schema, _ := oapi.ResolveSchema(paramObj.Schema)
+		params = append(params, parameter{
+			Name:     paramObj.Name,
+			GoName:   names.allocate(goName(paramObj.Name)),
+			Location: paramObj.Location,
+			Required: paramObj.Required || paramObj.Location == openapi3.ParameterLocationPath,
+			Type:     parameterType(schema),
+		})
+	}
+	return params
+}
+
+func parameterType(schema map[string]interface{}) string {
+	format, _ := schema["format"].(string)
+	switch schemaType(schema) {
+	case "integer":
+		if format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		items, _ := openapi3.SchemaMap(schema["items"])
+		itemType := parameterType(items)
+		if strings.HasPrefix(itemType, "[]") {
+			itemType = "string"
+		}
+		return "[]" + itemType
+	default:
+		return "string"
+	}
+}
+
+func collectRequestBody(oapi *openapi3.OpenAPIObject, types *typeGenerator, opName string, body openapi3.RequestBody) *requestBody {
+	bodyObj := oapi.ResolveRequestBody(body)
+	if bodyObj == nil {
+		return nil
+	}
+
+	hint := opName + "RequestBody"
+	if id, ok := openapi3.RefID(body, "#/components/requestBodies/"); ok {
+		hint = goName(id)
+	}
+
+	result := &requestBody{Required: bodyObj.Required}
+	result.MediaType, result.Type = contentType(types, bodyObj.Content, hint)
+	return result
+}
+
+func collectResponses(oapi *openapi3.OpenAPIObject, types *typeGenerator, opName string, responses map[string]openapi3.Response) []response {
+	var codes []string
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var results []response
+	for _, code := range codes {
+		responseObj := oapi.ResolveResponse(responses[code])
+		if responseObj == nil {
+			continue
+		}
+
+		codeName := responseCodeName(code)
+		hint := opName + codeName + "ResponseBody"
+		if id, ok := openapi3.RefID(responses[code], "#/components/responses/"); ok {
+			hint = goName(id)
+		}
+
+		result := response{
+			StatusCode:  code,
+			CodeName:    codeName,
+			GoName:      opName + codeName,
+			Description: responseObj.Description,
+		}
+		result.MediaType, result.Type = contentType(types, responseObj.Content, hint)
+		results = append(results, result)
+	}
+	return results
+}
+
+// responseCodeName returns the status code in Go identifiers, e.g. 200, 2XX,
+// Default.
+func responseCodeName(code string) string {
+	if code == "default" {
+		return "Default"
+	}
+	return strings.ToUpper(code)
+}
+
+// contentType selects the media type of a content map, preferring JSON, and
+// returns the Go type of JSON content.
+func contentType(types *typeGenerator, content map[string]openapi3.MediaTypeObject, hint string) (mediaType string, goType string) {
+	if mediaTypeObj, ok := content[jsonMediaType]; ok {
+		return jsonMediaType, types.cachedType(mediaTypeObj.Schema, hint)
+	}
+
+	var mediaTypes []string
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	if len(mediaTypes) > 0 {
+		return mediaTypes[0], ""
+	}
+	return "", ""
+}
@@ -0,0 +1,304 @@
+package codegen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// GenerateServer generates a Go package with types of component schemas, a
+// ServerInterface with a method for each operation, and a net/http router
+// adapter calling the ServerInterface.
+func GenerateServer(oapi *openapi3.OpenAPIObject, config Config) ([]byte, error) {
+	f := newFile(config)
+	operations := listOperations(oapi)
+	types := newTypeGenerator(oapi, f.imports, reservedNames(operations,
+		"ServerInterface", "NewRouter", "ParamError", "BodyError"))
+	collectOperations(oapi, types, operations)
+
+	f.use("context", "net/http", "strings")
+	f.printf("%s\n", types.declarations())
+
+	f.printf("// ServerInterface is implemented by servers of %s.\n", oapi.Info.Title)
+	f.printf("type ServerInterface interface {\n")
+	for _, op := range operations {
+		f.printf("%s", indent(operationDoc(op, "handles")))
+		f.printf("\t%s(ctx context.Context, request %sRequest) (%sResponse, error)\n", op.Name, op.Name, op.Name)
+	}
+	f.printf("}\n\n")
+
+	for _, op := range operations {
+		writeRequestType(f, op)
+		writeResponseTypes(f, op)
+		writeRequestDecoder(f, op)
+		writeHandler(f, op)
+	}
+
+	f.printf("// NewRouter returns a HTTP handler routing requests to the server.\n")
+	f.printf("func NewRouter(server ServerInterface) http.Handler {\n")
+	f.printf("\treturn &router{\n\t\tserver: server,\n\t\troutes: []route{\n")
+	for _, op := range operations {
+		f.printf("\t\t\t{method: %s, segments: %#v, handle: handle%s},\n",
+			strconv.Quote(op.Method), pathSegments(op.Path), op.Name)
+	}
+	f.printf("\t\t},\n\t}\n}\n\n")
+
+	f.use("errors", "strconv")
+	f.printf("%s", serverRuntime)
+	return f.source(oapi)
+}
+
+// operationDoc returns the doc comment of the operation method, e.g.
+// "ListPets handles GET /pets." with the verb "handles".
+func operationDoc(op operation, verb string) string {
+	doc := op.Name + " " + verb + " " + op.Method + " " + op.Path + "."
+	if op.Object.Summary != "" {
+		doc += "\n\n" + op.Object.Summary
+	}
+	return docComment(doc)
+}
+
+func pathSegments(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func writeRequestType(f *file, op operation) {
+	f.printf("// %sRequest is the request of %s.\n", op.Name, op.Name)
+	f.printf("type %sRequest struct {\n", op.Name)
+	for _, param := range op.Params {
+		f.printf("\t// %s is the %s parameter %s.\n", param.GoName, param.Location, strconv.Quote(param.Name))
+		f.printf("\t%s %s\n", param.GoName, paramFieldType(param))
+	}
+	if op.Body != nil {
+		switch fieldType := op.Body.FieldType(); fieldType {
+		case "url.Values":
+			f.use("net/url")
+			f.printf("\tBody url.Values\n")
+		case "io.Reader":
+			f.use("io")
+			f.printf("\tBody io.Reader\n")
+		default:
+			f.printf("\tBody %s\n", fieldType)
+		}
+	}
+	f.printf("}\n\n")
+}
+
+func paramFieldType(param parameter) string {
+	if param.Required || strings.HasPrefix(param.Type, "[]") {
+		return param.Type
+	}
+	return "*" + param.Type
+}
+
+func writeResponseTypes(f *file, op operation) {
+	method := "write" + op.Name + "Response"
+	f.printf("// %sResponse is implemented by responses of %s.\n", op.Name, op.Name)
+	f.printf("type %sResponse interface {\n\t%s(w http.ResponseWriter) error\n}\n\n", op.Name, method)
+
+	for _, resp := range op.Responses {
+		code := resp.Code()
+		f.printf("%s", docComment(resp.Description))
+		switch {
+		case resp.Type != "" && code != 0:
+			f.use("encoding/json")
+			f.printf("type %sJSONResponse %s\n\n", resp.GoName, strings.TrimPrefix(resp.Type, "*"))
+			f.printf("func (resp %sJSONResponse) %s(w http.ResponseWriter) error {\n", resp.GoName, method)
+			f.printf("\tw.Header().Set(\"Content-Type\", %s)\n", strconv.Quote(resp.MediaType))
+			f.printf("\tw.WriteHeader(%d)\n", code)
+			f.printf("\treturn json.NewEncoder(w).Encode(resp)\n}\n\n")
+		case resp.Type != "":
+			f.use("encoding/json")
+			f.printf("type %sJSONResponse struct {\n\tStatusCode int\n\tBody %s\n}\n\n", resp.GoName, resp.Type)
+			f.printf("func (resp %sJSONResponse) %s(w http.ResponseWriter) error {\n", resp.GoName, method)
+			f.printf("\tw.Header().Set(\"Content-Type\", %s)\n", strconv.Quote(resp.MediaType))
+			f.printf("\tw.WriteHeader(resp.StatusCode)\n")
+			f.printf("\treturn json.NewEncoder(w).Encode(resp.Body)\n}\n\n")
+		default:
+			f.use("io")
+			f.printf("type %sResponse struct {\n", resp.GoName)
+			if code == 0 {
+				f.printf("\tStatusCode int\n")
+			}
+			f.printf("\tContentType string\n\tBody io.Reader\n}\n\n")
+			f.printf("func (resp %sResponse) %s(w http.ResponseWriter) error {\n", resp.GoName, method)
+			f.printf("\tif resp.ContentType != \"\" {\n\t\tw.Header().Set(\"Content-Type\", resp.ContentType)\n\t}\n")
+			if code == 0 {
+				f.printf("\tw.WriteHeader(resp.StatusCode)\n")
+			} else {
+				f.printf("\tw.WriteHeader(%d)\n", code)
+			}
+			f.printf("\tif resp.Body == nil {\n\t\treturn nil\n\t}\n")
+			f.printf("\t_, err := io.Copy(w, resp.Body)\n\treturn err\n}\n\n")
+		}
+	}
+}
+
+func writeRequestDecoder(f *file, op operation) {
+	f.printf("func decode%sRequest(r *http.Request, pathParams map[string]string) (%sRequest, error) {\n", op.Name, op.Name)
+	f.printf("\tvar req %sRequest\n", op.Name)
+	for _, param := range op.Params {
+		f.printf("\tif values := paramValues(r, pathParams, %s, %s); len(values) > 0 {\n",
+			strconv.Quote(string(param.Location)), strconv.Quote(param.Name))
+		if strings.HasPrefix(param.Type, "[]") {
+			f.printf("\t\tfor _, value := range values {\n")
+			f.printf("%s", indent(indent(indent(parseValue(param.Name, strings.TrimPrefix(param.Type, "[]"))))))
+			f.printf("\t\t\treq.%s = append(req.%s, v)\n", param.GoName, param.GoName)
+			f.printf("\t\t}\n")
+		} else {
+			f.printf("\t\tvalue := values[0]\n")
+			f.printf("%s", indent(indent(parseValue(param.Name, param.Type))))
+			if param.Required {
+				f.printf("\t\treq.%s = v\n", param.GoName)
+			} else {
+				f.printf("\t\treq.%s = &v\n", param.GoName)
+			}
+		}
+		if param.Required {
+			f.printf("\t} else {\n\t\treturn req, &ParamError{Name: %s, Err: errMissingParam}\n", strconv.Quote(param.Name))
+		}
+		f.printf("\t}\n")
+	}
+
+	if op.Body != nil {
+		if op.Body.Type != "" {
+			f.use("encoding/json")
+			f.printf("\tvar body %s\n", strings.TrimPrefix(op.Body.Type, "*"))
+			f.printf("\tif err := json.NewDecoder(r.Body).Decode(&body); err == nil {\n\t\treq.Body = &body\n")
+			if op.Body.Required {
+				f.printf("\t} else {\n")
+			} else {
+				f.use("io")
+				f.printf("\t} else if err != io.EOF {\n")
+			}
+			f.printf("\t\treturn req, &BodyError{Err: err}\n\t}\n")
+		} else if op.Body.MediaType == formMediaType {
+			f.printf("\tif err := r.ParseForm(); err != nil {\n\t\treturn req, &BodyError{Err: err}\n\t}\n")
+			f.printf("\treq.Body = r.PostForm\n")
+		} else {
+			f.printf("\treq.Body = r.Body\n")
+		}
+	}
+	f.printf("\treturn req, nil\n}\n\n")
+}
+
+// parseValue returns code parsing the string variable value into variable v.
+func parseValue(name string, goType string) string {
+	var parse string
+	switch goType {
+	case "int64":
+		parse = "v, err := strconv.ParseInt(value, 10, 64)\n"
+	case "int32":
+		parse = "v64, err := strconv.ParseInt(value, 10, 32)\nv := int32(v64)\n"
+	case "float64":
+		parse = "v, err := strconv.ParseFloat(value, 64)\n"
+	case "bool":
+		parse = "v, err := strconv.ParseBool(value)\n"
+	default:
+		return "v := value\n"
+	}
+	return parse + "if err != nil {\n\treturn req, &ParamError{Name: " + strconv.Quote(name) + ", Err: err}\n}\n"
+}
+
+func writeHandler(f *file, op operation) {
+	f.printf("func handle%s(server ServerInterface, w http.ResponseWriter, r *http.Request, pathParams map[string]string) {\n", op.Name)
+	f.printf("\treq, err := decode%sRequest(r, pathParams)\n", op.Name)
+	f.printf("\tif err != nil {\n\t\thttp.Error(w, err.Error(), http.StatusBadRequest)\n\t\treturn\n\t}\n")
+	f.printf("\tresp, err := server.%s(r.Context(), req)\n", op.Name)
+	f.printf("\tif err == nil && resp == nil {\n\t\terr = errNoResponse\n\t}\n")
+	f.printf("\tif err != nil {\n\t\thttp.Error(w, err.Error(), http.StatusInternalServerError)\n\t\treturn\n\t}\n")
+	// The status code may have been written, so errors can only be logged.
+	f.use("log")
+	f.printf("\tif err := resp.write%sResponse(w); err != nil {\n", op.Name)
+	f.printf("\t\tlog.Printf(\"%%s %%s: failed to write response: %%v\", r.Method, r.URL.Path, err)\n\t}\n}\n\n")
+}
+
+const serverRuntime = `var errMissingParam = errors.New("missing required parameter")
+var errNoResponse = errors.New("no response")
+
+// ParamError is returned when a request parameter is invalid.
+type ParamError struct {
+	Name string
+	Err  error
+}
+
+func (err *ParamError) Error() string {
+	return "invalid parameter " + strconv.Quote(err.Name) + ": " + err.Err.Error()
+}
+
+// BodyError is returned when a request body is invalid.
+type BodyError struct {
+	Err error
+}
+
+func (err *BodyError) Error() string {
+	return "invalid request body: " + err.Err.Error()
+}
+
+func paramValues(r *http.Request, pathParams map[string]string, location string, name string) []string {
+	switch location {
+	case "path":
+		if value, ok := pathParams[name]; ok {
+			return []string{value}
+		}
+	case "query":
+		return r.URL.Query()[name]
+	case "header":
+		return r.Header[http.CanonicalHeaderKey(name)]
+	case "cookie":
+		if cookie, err := r.Cookie(name); err == nil {
+			return []string{cookie.Value}
+		}
+	}
+	return nil
+}
+
+type route struct {
+	method   string
+	segments []string
+	handle   func(server ServerInterface, w http.ResponseWriter, r *http.Request, pathParams map[string]string)
+}
+
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = segments[i]
+		} else if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+type router struct {
+	server ServerInterface
+	routes []route
+}
+
+func (rt *router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	pathMatched := false
+	for _, route := range rt.routes {
+		pathParams, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if route.method == r.Method {
+			route.handle(rt.server, w, r, pathParams)
+			return
+		}
+	}
+
+	if pathMatched {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	} else {
+		http.NotFound(w, r)
+	}
+}
+`
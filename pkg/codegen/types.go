@@ -0,0 +1,279 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// typeGenerator declares Go types for JSON schemas.
+type typeGenerator struct {
+	oapi    *openapi3.OpenAPIObject
+	names   nameSet
+	imports map[string]bool
+
+	// schemaNames maps component schema IDs to Go type names.
+	schemaNames map[string]string
+	// hintTypes maps type name hints to the declared types, so that
+	// component request bodies and responses are declared once.
+	hintTypes map[string]string
+	decls     []string
+}
+
+// newTypeGenerator declares types of component schemas, named without
+// conflicting with the reserved names.
+func newTypeGenerator(oapi *openapi3.OpenAPIObject, imports map[string]bool, reserved []string) *typeGenerator {
+	g := &typeGenerator{
+		oapi:        oapi,
+		names:       nameSet{},
+		imports:     imports,
+		schemaNames: map[string]string{},
+		hintTypes:   map[string]string{},
+	}
+	for _, name := range reserved {
+		g.names[name] = true
+	}
+
+	var ids []string
+	for id := range oapi.Components.Schemas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		g.schemaNames[id] = g.names.allocate(goName(id))
+	}
+	for _, id := range ids {
+		schema, _ := openapi3.SchemaMap(oapi.Components.Schemas[id])
+		g.declareNamed(g.schemaNames[id], schema)
+	}
+	return g
+}
+
+// declareNamed declares a named type for the schema.
+func (g *typeGenerator) declareNamed(name string, schema map[string]interface{}) {
+	var decl strings.Builder
+	decl.WriteString(docComment(schemaDescription(schema)))
+
+	if _, isRef := schema["$ref"]; !isRef && isObjectSchema(schema) {
+		fmt.Fprintf(&decl, "type %s %s\n", name, g.structType(name, schema))
+		g.decls = append(g.decls, decl.String())
+		return
+	}
+
+	fmt.Fprintf(&decl, "type %s %s\n", name, g.goType(schema, name, true))
+	if enum, ok := schema["enum"].([]interface{}); ok && schema["type"] == "string" {
+		decl.WriteString("\nconst (\n")
+		for _, value := range enum {
+			str, ok := value.(string)
+			if !ok {
+				continue
+			}
+			constName := g.names.allocate(name + goName(str))
+			fmt.Fprintf(&decl, "\t%s %s = %s\n", constName, name, strconv.Quote(str))
+		}
+		decl.WriteString(")\n")
+	}
+	g.decls = append(g.decls, decl.String())
+}
+
+// typeFor returns the Go type of the schema, declaring a type with the hinted
+// name if the schema is an inline object.
+func (g *typeGenerator) typeFor(schema interface{}, hint string) string {
+	m, _ := openapi3.SchemaMap(schema)
+	return g.goType(m, hint, true)
+}
+
+// cachedType is typeFor, returning the same type for the same hint.
+func (g *typeGenerator) cachedType(schema interface{}, hint string) string {
+	if goType, ok := g.hintTypes[hint]; ok {
+		return goType
+	}
+	goType := g.typeFor(schema, hint)
+	g.hintTypes[hint] = goType
+	return goType
+}
+
+func (g *typeGenerator) goType(schema map[string]interface{}, hint string, required bool) string {
+	if schema == nil {
+		return "interface{}"
+	}
+
+	if id, ok := openapi3.RefID(schema, "#/components/schemas/"); ok {
+		name, exists := g.schemaNames[id]
+		if !exists {
+			return "interface{}"
+		}
+		return g.optional(name, required)
+	}
+
+	nullable, _ := schema["nullable"].(bool)
+	if nullable {
+		required = false
+	}
+
+	if _, ok := schema["allOf"]; ok {
+		return g.optional(g.declareStruct(hint, schema), required)
+	}
+	if _, ok := schema["oneOf"]; ok {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if _, ok := schema["anyOf"]; ok {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+
+	format, _ := schema["format"].(string)
+	switch schemaType(schema) {
+	case "string":
+		return g.optional("string", required)
+	case "integer":
+		if format == "int32" {
+			return g.optional("int32", required)
+		}
+		return g.optional("int64", required)
+	case "number":
+		if format == "float" {
+			return g.optional("float32", required)
+		}
+		return g.optional("float64", required)
+	case "boolean":
+		return g.optional("bool", required)
+	case "array":
+		items, _ := openapi3.SchemaMap(schema["items"])
+		return "[]" + g.goType(items, hint+"Item", true)
+	case "object":
+		if isObjectSchema(schema) {
+			return g.optional(g.declareStruct(hint, schema), required)
+		}
+		if additional, ok := openapi3.SchemaMap(schema["additionalProperties"]); ok {
+			return "map[string]" + g.goType(additional, hint+"Value", true)
+		}
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+func (g *typeGenerator) optional(typeName string, required bool) string {
+	if required {
+		return typeName
+	}
+	return "*" + typeName
+}
+
+func (g *typeGenerator) declareStruct(hint string, schema map[string]interface{}) string {
+	name := g.names.allocate(hint)
+	var decl strings.Builder
+	decl.WriteString(docComment(schemaDescription(schema)))
+	fmt.Fprintf(&decl, "type %s %s\n", name, g.structType(name, schema))
+	g.decls = append(g.decls, decl.String())
+	return name
+}
+
+func (g *typeGenerator) structType(name string, schema map[string]interface{}) string {
+	var fields strings.Builder
+	fields.WriteString("struct {\n")
+
+	// Embed referenced schemas and merge properties of inline schemas.
+	schemas := []map[string]interface{}{schema}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			subSchema, ok := openapi3.SchemaMap(sub)
+			if !ok {
+				continue
+			}
+			if id, ok := openapi3.RefID(subSchema, "#/components/schemas/"); ok {
+				if embedded, exists := g.schemaNames[id]; exists {
+					fmt.Fprintf(&fields, "\t%s\n", embedded)
+				}
+				continue
+			}
+			schemas = append(schemas, subSchema)
+		}
+	}
+
+	fieldNames := nameSet{}
+	for _, s := range schemas {
+		required := map[string]bool{}
+		if list, ok := s["required"].([]interface{}); ok {
+			for _, prop := range list {
+				if propName, ok := prop.(string); ok {
+					required[propName] = true
+				}
+			}
+		}
+
+		properties, _ := openapi3.SchemaMap(s["properties"])
+		var propNames []string
+		for propName := range properties {
+			propNames = append(propNames, propName)
+		}
+		sort.Strings(propNames)
+
+		for _, propName := range propNames {
+			propSchema, _ := openapi3.SchemaMap(properties[propName])
+			fieldName := fieldNames.allocate(goName(propName))
+			fieldType := g.goType(propSchema, name+fieldName, required[propName])
+
+			tag := propName
+			if !required[propName] {
+				tag += ",omitempty"
+			}
+			fields.WriteString(indent(docComment(schemaDescription(propSchema))))
+			fmt.Fprintf(&fields, "\t%s %s `json:%s`\n", fieldName, fieldType, strconv.Quote(tag))
+		}
+	}
+
+	fields.WriteString("}")
+	return fields.String()
+}
+
+func (g *typeGenerator) declarations() string {
+	return strings.Join(g.decls, "\n")
+}
+
+func isObjectSchema(schema map[string]interface{}) bool {
+	if _, ok := schema["allOf"]; ok {
+		return true
+	}
+	_, hasProperties := schema["properties"]
+	return hasProperties && (schema["type"] == nil || schema["type"] == "object")
+}
+
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		// e.g. OpenAPI 3.1 ["string", "null"]
+		for _, item := range t {
+			if str, ok := item.(string); ok && str != "null" {
+				return str
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func schemaDescription(schema map[string]interface{}) string {
+	description, _ := schema["description"].(string)
+	return description
+}
+
+func indent(str string) string {
+	if str == "" {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(str, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "\t" + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
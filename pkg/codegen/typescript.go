@@ -0,0 +1,337 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// GenerateTypeScript generates TypeScript type definitions of component
+// schemas, and a map of paths to operations with their parameters, request
+// body and response types.
+func GenerateTypeScript(oapi *openapi3.OpenAPIObject) ([]byte, error) {
+	g := &tsGenerator{oapi: oapi}
+	g.printf("// Code generated by openapi3-gen from %s. DO NOT EDIT.\n", jsQuote(oapi.Info.Title))
+
+	var ids []string
+	for id := range oapi.Components.Schemas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		schema, _ := openapi3.SchemaMap(oapi.Components.Schemas[id])
+		g.printf("\n%s", g.docComment(schemaDescription(schema), ""))
+		if isObjectSchema(schema) && schema["allOf"] == nil && schema["nullable"] != true {
+			g.printf("export interface %s %s\n", tsName(id), g.tsType(schema, ""))
+		} else {
+			g.printf("export type %s = %s;\n", tsName(id), g.tsType(schema, ""))
+		}
+	}
+
+	if len(oapi.Components.RequestBodies) > 0 {
+		g.printf("\nexport interface RequestBodies {\n")
+		for _, id := range openapi3.SortedKeys(oapi.Components.RequestBodies) {
+			body := oapi.Components.RequestBodies[id]
+			g.printf("%s", g.docComment(body.Description, "  "))
+			g.printf("  %s: %s;\n", tsKey(id), g.contentType(body.Content, "  "))
+		}
+		g.printf("}\n")
+	}
+
+	if len(oapi.Components.Responses) > 0 {
+		g.printf("\nexport interface Responses {\n")
+		for _, id := range openapi3.SortedKeys(oapi.Components.Responses) {
+			response := oapi.Components.Responses[id]
+			g.printf("%s", g.docComment(response.Description, "  "))
+			g.printf("  %s: %s;\n", tsKey(id), g.contentType(response.Content, "  "))
+		}
+		g.printf("}\n")
+	}
+
+	g.printf("\nexport interface Paths {\n")
+	var paths []string
+	for path := range oapi.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := oapi.Paths[path]
+		g.printf("  %s: {\n", tsKey(path))
+		for _, method := range openapi3.Methods {
+			if op := pathItem.GetOperation(method); op != nil {
+				g.writeOperation(pathItem, method, op)
+			}
+		}
+		g.printf("  };\n")
+	}
+	g.printf("}\n")
+
+	return g.buf.Bytes(), nil
+}
+
+type tsGenerator struct {
+	oapi *openapi3.OpenAPIObject
+	buf  bytes.Buffer
+}
+
+func (g *tsGenerator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *tsGenerator) docComment(text string, indent string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		return indent + "/** " + text + " */\n"
+	}
+	var doc strings.Builder
+	doc.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		doc.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	doc.WriteString(indent + " */\n")
+	return doc.String()
+}
+
+func (g *tsGenerator) writeOperation(pathItem openapi3.PathItemObject, method string, op *openapi3.OperationObject) {
+	doc := op.Summary
+	if op.Description != "" {
+		doc = strings.TrimSpace(doc + "\n\n" + op.Description)
+	}
+	g.printf("%s", g.docComment(doc, "    "))
+	g.printf("    %s: {\n", strings.ToLower(method))
+
+	params := map[openapi3.ParameterLocation][]*openapi3.ParameterObject{}
+	for i := range pathItem.Parameters {
+		param := &pathItem.Parameters[i]
+		params[param.Location] = append(params[param.Location], param)
+	}
+	for _, param := range op.Parameters {
+		if paramObj := g.oapi.ResolveParameter(param); paramObj != nil {
+			params[paramObj.Location] = append(params[paramObj.Location], paramObj)
+		}
+	}
+	if len(params) > 0 {
+		g.printf("      parameters: {\n")
+		for _, location := range []openapi3.ParameterLocation{
+			openapi3.ParameterLocationPath,
+			openapi3.ParameterLocationQuery,
+			openapi3.ParameterLocationHeader,
+			openapi3.ParameterLocationCookie,
+		} {
+			if len(params[location]) == 0 {
+				continue
+			}
+			required := false
+			for _, param := range params[location] {
+				required = required || param.Required || location == openapi3.ParameterLocationPath
+			}
+			g.printf("        %s%s: {\n", location, optionalMark(required))
+			for _, param := range params[location] {
+				paramRequired := param.Required || location == openapi3.ParameterLocationPath
+				g.printf("%s", g.docComment(param.Description, "          "))
+				g.printf("          %s%s: %s;\n", tsKey(param.Name), optionalMark(paramRequired), g.tsType(param.Schema, "          "))
+			}
+			g.printf("        };\n")
+		}
+		g.printf("      };\n")
+	}
+
+	if op.RequestBody != nil {
+		body := g.oapi.ResolveRequestBody(op.RequestBody)
+		if id, ok := openapi3.RefID(op.RequestBody, "#/components/requestBodies/"); ok {
+			g.printf("      requestBody%s: RequestBodies[%s];\n", optionalMark(body != nil && body.Required), jsQuote(id))
+		} else if body != nil {
+			g.printf("      requestBody%s: %s;\n", optionalMark(body.Required), g.contentType(body.Content, "      "))
+		}
+	}
+
+	g.printf("      responses: {\n")
+	for _, code := range openapi3.SortedKeys(op.Responses) {
+		key := code
+		if code != "default" && !isNumber(code) {
+			key = jsQuote(code)
+		}
+		if id, ok := openapi3.RefID(op.Responses[code], "#/components/responses/"); ok {
+			g.printf("        %s: Responses[%s];\n", key, jsQuote(id))
+		} else if response := g.oapi.ResolveResponse(op.Responses[code]); response != nil {
+			g.printf("%s", g.docComment(response.Description, "        "))
+			g.printf("        %s: %s;\n", key, g.contentType(response.Content, "        "))
+		}
+	}
+	g.printf("      };\n")
+	g.printf("    };\n")
+}
+
+// contentType returns the type of JSON content, unknown for other content,
+// or void for no content.
+func (g *tsGenerator) contentType(content map[string]openapi3.MediaTypeObject, indent string) string {
+	if mediaType, ok := content[jsonMediaType]; ok {
+		return g.tsType(mediaType.Schema, indent)
+	}
+	if len(content) > 0 {
+		return "unknown"
+	}
+	return "void"
+}
+
+func (g *tsGenerator) tsType(schemaValue interface{}, indent string) string {
+	schema, ok := openapi3.SchemaMap(schemaValue)
+	if !ok {
+		return "unknown"
+	}
+
+	t := g.nonNullType(schema, indent)
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		t = t + " | null"
+	}
+	return t
+}
+
+func (g *tsGenerator) nonNullType(schema map[string]interface{}, indent string) string {
+	if id, ok := openapi3.RefID(schema, "#/components/schemas/"); ok {
+		return tsName(id)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		var values []string
+		for _, value := range enum {
+			data, err := json.Marshal(value)
+			if err == nil {
+				values = append(values, string(data))
+			}
+		}
+		return strings.Join(values, " | ")
+	}
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		parts := g.compositeTypes(allOf, indent)
+		if props, hasProps := schema["properties"]; hasProps {
+			rest := map[string]interface{}{"properties": props, "required": schema["required"]}
+			parts = append(parts, g.objectType(rest, indent))
+		}
+		return strings.Join(parts, " & ")
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		return strings.Join(g.compositeTypes(oneOf, indent), " | ")
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		return strings.Join(g.compositeTypes(anyOf, indent), " | ")
+	}
+
+	switch schemaType(schema) {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := g.tsType(schema["items"], indent)
+		if strings.ContainsAny(item, "|&") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object":
+		return g.objectType(schema, indent)
+	default:
+		return "unknown"
+	}
+}
+
+func (g *tsGenerator) compositeTypes(schemas []interface{}, indent string) []string {
+	var parts []string
+	for _, sub := range schemas {
+		part := g.tsType(sub, indent)
+		if strings.ContainsAny(part, "|&") {
+			part = "(" + part + ")"
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func (g *tsGenerator) objectType(schema map[string]interface{}, indent string) string {
+	properties, _ := openapi3.SchemaMap(schema["properties"])
+	additional := schema["additionalProperties"]
+	if len(properties) == 0 {
+		if _, ok := openapi3.SchemaMap(additional); ok {
+			return "{ [key: string]: " + g.tsType(additional, indent) + " }"
+		}
+		return "{ [key: string]: unknown }"
+	}
+
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, name := range list {
+			if str, ok := name.(string); ok {
+				required[str] = true
+			}
+		}
+	}
+
+	inner := indent + "  "
+	var obj strings.Builder
+	obj.WriteString("{\n")
+	for _, name := range openapi3.SortedKeys(properties) {
+		propSchema, _ := openapi3.SchemaMap(properties[name])
+		obj.WriteString(g.docComment(schemaDescription(propSchema), inner))
+		fmt.Fprintf(&obj, "%s%s%s: %s;\n", inner, tsKey(name), optionalMark(required[name]), g.tsType(propSchema, inner))
+	}
+	if _, ok := openapi3.SchemaMap(additional); ok {
+		fmt.Fprintf(&obj, "%s[key: string]: unknown;\n", inner)
+	}
+	obj.WriteString(indent + "}")
+	return obj.String()
+}
+
+var tsIdentifierFormat = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsName converts a component ID to a TypeScript type name.
+func tsName(id string) string {
+	if tsIdentifierFormat.MatchString(id) {
+		return id
+	}
+	return goName(id)
+}
+
+// tsKey returns a property key, quoted if not an identifier.
+func tsKey(key string) string {
+	if tsIdentifierFormat.MatchString(key) {
+		return key
+	}
+	return jsQuote(key)
+}
+
+func optionalMark(required bool) string {
+	if required {
+		return ""
+	}
+	return "?"
+}
+
+func isNumber(str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, c := range str {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// jsQuote returns a JavaScript string literal.
+func jsQuote(str string) string {
+	data, _ := json.Marshal(str)
+	return string(data)
+}
@@ -0,0 +1,130 @@
+package contract
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/router"
+)
+
+// T reports violations; it is implemented by *testing.T.
+type T interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Checker checks requests and responses of handlers against the operations of
+// an OpenAPI document, reporting violations as test failures: requests not
+// matching an operation, invalid parameters and bodies, undeclared status
+// codes, missing required headers, and bodies violating their schema.
+type Checker struct {
+	t      T
+	router *router.Router
+	// basePath is the path of the first server URL, stripped from request
+	// paths.
+	basePath string
+}
+
+// New returns a checker of the document, failing the test if schemas of the
+// document cannot be compiled.
+func New(t T, oapi *openapi3.OpenAPIObject) *Checker {
+	t.Helper()
+	r, err := router.New(oapi)
+	if err != nil {
+		t.Fatalf("contract: %v", err)
+	}
+	return &Checker{t: t, router: r, basePath: router.BasePath(oapi)}
+}
+
+// Handler wraps the handler, checking each request it serves and its
+// response.
+func (c *Checker) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := c.CheckRequest(r)
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		if route != nil {
+			c.checkResponse(r, route, rec.status, w.Header(), rec.body.Bytes())
+		}
+	})
+}
+
+// Transport wraps the transport, checking each request sent and its
+// response, e.g. requests of httptest.Server clients:
+//
+//	client := server.Client()
+//	client.Transport = checker.Transport(client.Transport)
+func (c *Checker) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripper(func(r *http.Request) (*http.Response, error) {
+		route := c.CheckRequest(r)
+		resp, err := base.RoundTrip(r)
+		if err != nil || route == nil {
+			return resp, err
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		c.checkResponse(r, route, resp.StatusCode, resp.Header, body)
+		return resp, nil
+	})
+}
+
+// CheckRequest checks the request, returning the route of its operation, or
+// nil if no operation matches the request.
+func (c *Checker) CheckRequest(r *http.Request) *router.Route {
+	c.t.Helper()
+	route, params, err := c.router.Match(r.Method, router.StripBasePath(r.URL.Path, c.basePath))
+	if err != nil {
+		c.t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+		return nil
+	}
+	violations, err := route.ValidateRequest(r, params)
+	if err != nil {
+		c.t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+		return route
+	}
+	for _, violation := range violations {
+		c.t.Errorf("%s %s: request %v", r.Method, r.URL.Path, violation)
+	}
+	return route
+}
+
+func (c *Checker) checkResponse(r *http.Request, route *router.Route, status int, header http.Header, body []byte) {
+	c.t.Helper()
+	for _, violation := range route.ValidateResponse(status, header, body) {
+		c.t.Errorf("%s %s: response %v", r.Method, r.URL.Path, violation)
+	}
+}
+
+// recorder records the status code and body written to the response.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(data []byte) (int, error) {
+	rec.body.Write(data)
+	return rec.ResponseWriter.Write(data)
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,128 @@
+package contract
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+paths:
+  /users/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: integer}}
+    get:
+      responses:
+        "200":
+          description: OK
+          headers:
+            X-Rate-Limit: {required: true, schema: {type: integer}}
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name: {type: string}
+        "404": {description: Not found}
+`
+
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.Errorf(format, args...)
+}
+
+func TestChecker(t *testing.T) {
+	Convey("Checker", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+		ft := &fakeT{}
+		c := New(ft, oapi)
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/users/1":
+				w.Header().Set("X-Rate-Limit", "100")
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"name": "Test"}`))
+			case "/users/2":
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"name": 2}`))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+
+		Convey("should check handlers", func() {
+			serve := func(method string, path string) {
+				c.Handler(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+			}
+
+			serve("GET", "/users/1")
+			So(ft.errors, ShouldBeEmpty)
+
+			serve("GET", "/users/2")
+			serve("GET", "/users/a")
+			serve("POST", "/users/1")
+			So(ft.errors, ShouldResemble, []string{
+				"GET /users/2: response header X-Rate-Limit: is required",
+				"GET /users/2: response body/name: must be string, got integer",
+				"GET /users/a: request path id: must be integer, got string",
+				"GET /users/a: response status: status code 500 is not declared",
+				"POST /users/1: method not allowed",
+			})
+		})
+
+		Convey("should strip the base path of the server URL", func() {
+			oapi, err := openapi3.Load([]byte(testDocument + "servers:\n- url: https://api.example.com/v1\n"))
+			So(err, ShouldBeNil)
+			c := New(ft, oapi)
+			serve := func(method string, path string) {
+				c.Handler(http.StripPrefix("/v1", handler)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+			}
+
+			serve("GET", "/v1/users/1")
+			So(ft.errors, ShouldBeEmpty)
+
+			serve("GET", "/v1/users/a")
+			So(ft.errors, ShouldResemble, []string{
+				"GET /v1/users/a: request path id: must be integer, got string",
+				"GET /v1/users/a: response status: status code 500 is not declared",
+			})
+		})
+
+		Convey("should check clients of servers", func() {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			client := server.Client()
+			client.Transport = c.Transport(client.Transport)
+
+			resp, err := client.Get(server.URL + "/users/2")
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+			body, err := ioutil.ReadAll(resp.Body)
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, `{"name": 2}`)
+			So(ft.errors, ShouldResemble, []string{
+				"GET /users/2: response header X-Rate-Limit: is required",
+				"GET /users/2: response body/name: must be string, got integer",
+			})
+		})
+	})
+}
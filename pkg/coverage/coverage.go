@@ -0,0 +1,115 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+)
+
+// Handler is a function matching one of the handler signatures.
+type Handler struct {
+	Package    string         `json:"package"`
+	Name       string         `json:"name"`
+	Position   token.Position `json:"-"`
+	Location   string         `json:"location"`
+	Operations []string       `json:"operations,omitempty"`
+}
+
+func (h Handler) Documented() bool {
+	return len(h.Operations) > 0
+}
+
+// Finder collects handler functions from scanned files.
+type Finder struct {
+	signatures []Signature
+	handlers   []Handler
+}
+
+func NewFinder(signatures []Signature) *Finder {
+	return &Finder{signatures: signatures}
+}
+
+func (f *Finder) Process(fset *token.FileSet, file *ast.File) {
+	qualifiers := importQualifiers(file)
+	qualify := func(name string) string {
+		if qualifier, ok := qualifiers[name]; ok {
+			return qualifier
+		}
+		return name
+	}
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		params := fieldTypes(funcDecl.Type.Params, qualify)
+		results := fieldTypes(funcDecl.Type.Results, qualify)
+		for _, sig := range f.signatures {
+			if !sig.match(params, results) {
+				continue
+			}
+			position := fset.Position(funcDecl.Pos())
+			f.handlers = append(f.handlers, Handler{
+				Package:  file.Name.Name,
+				Name:     funcName(funcDecl),
+				Position: position,
+				Location: position.String(),
+			})
+			break
+		}
+	}
+}
+
+// Report cross-references the found handlers with the operations declared
+// by the processor.
+func (f *Finder) Report(operations []processor.OperationDeclaration) *Report {
+	documented := map[token.Position][]string{}
+	for _, op := range operations {
+		documented[op.Position] = append(documented[op.Position], op.Method+" "+op.Path)
+	}
+
+	report := &Report{}
+	for _, handler := range f.handlers {
+		handler.Operations = documented[handler.Position]
+		if handler.Documented() {
+			report.Documented++
+		} else {
+			report.Undocumented = append(report.Undocumented, handler)
+		}
+		report.Handlers = append(report.Handlers, handler)
+	}
+
+	sort.SliceStable(report.Handlers, func(i, j int) bool {
+		return lessPosition(report.Handlers[i].Position, report.Handlers[j].Position)
+	})
+	sort.SliceStable(report.Undocumented, func(i, j int) bool {
+		return lessPosition(report.Undocumented[i].Position, report.Undocumented[j].Position)
+	})
+	return report
+}
+
+func funcName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+
+	recv := decl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + decl.Name.Name
+	}
+	return decl.Name.Name
+}
+
+func lessPosition(a token.Position, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+	return a.Offset < b.Offset
+}
@@ -0,0 +1,128 @@
+package coverage
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCoverage(t *testing.T) {
+	report := func(signatures []string, src string) *Report {
+		var sigs []Signature
+		for _, str := range signatures {
+			sig, err := ParseSignature(str)
+			So(err, ShouldBeNil)
+			sigs = append(sigs, sig)
+		}
+
+		psr := processor.New()
+		finder := NewFinder(sigs)
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+		So(err, ShouldBeNil)
+		psr.Process(fset, file)
+		finder.Process(fset, file)
+
+		return finder.Report(psr.Operations())
+	}
+
+	Convey("ParseSignature", t, func() {
+		sig, err := ParseSignature("func(w http.ResponseWriter, r *http.Request)")
+		So(err, ShouldBeNil)
+		So(sig, ShouldResemble, Signature{
+			Params: []string{"http.ResponseWriter", "*http.Request"},
+		})
+		So(sig.String(), ShouldEqual, DefaultSignature)
+
+		sig, err = ParseSignature("func(echo.Context) error")
+		So(err, ShouldBeNil)
+		So(sig.String(), ShouldEqual, "func(echo.Context) error")
+
+		_, err = ParseSignature("http.Handler")
+		So(err, ShouldBeError)
+	})
+
+	Convey("Finder", t, func() {
+		Convey("should report undocumented handlers", func() {
+			r := report([]string{DefaultSignature}, `
+				package main
+
+				import nethttp "net/http"
+
+				/*
+					@Operation GET /user - Get User
+				*/
+				func GetUser(w nethttp.ResponseWriter, r *nethttp.Request) {}
+
+				func DeleteUser(w nethttp.ResponseWriter, r *nethttp.Request) {}
+
+				type Handler struct {}
+
+				func (h *Handler) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {}
+
+				func helper(w nethttp.ResponseWriter) {}
+			`)
+
+			So(r.Total(), ShouldEqual, 3)
+			So(r.Documented, ShouldEqual, 1)
+			So(r.Handlers[0].Operations, ShouldResemble, []string{"GET /user"})
+			So(len(r.Undocumented), ShouldEqual, 2)
+			So(r.Undocumented[0].Name, ShouldEqual, "DeleteUser")
+			So(r.Undocumented[1].Name, ShouldEqual, "Handler.ServeHTTP")
+
+			var buf bytes.Buffer
+			So(r.WriteText(&buf), ShouldBeNil)
+			So(buf.String(), ShouldEqual, ""+
+				"handlers.go:11:5: main.DeleteUser is not documented\n"+
+				"handlers.go:15:5: main.Handler.ServeHTTP is not documented\n"+
+				"coverage: 33.3% (1/3 handlers documented)\n",
+			)
+		})
+
+		Convey("should match framework handler signatures", func() {
+			r := report([]string{"func(echo.Context) error"}, `
+				package main
+
+				import "github.com/labstack/echo/v4"
+
+				// @Operation GET /me - Get Me
+				func GetMe(c echo.Context) error { return nil }
+				func GetUser(c echo.Context) {}
+			`)
+
+			So(r.Total(), ShouldEqual, 1)
+			So(r.Documented, ShouldEqual, 1)
+			So(r.Coverage(), ShouldEqual, 100)
+		})
+
+		Convey("should write JSON report", func() {
+			r := report([]string{DefaultSignature}, `
+				package main
+
+				import "net/http"
+
+				func GetUser(w http.ResponseWriter, r *http.Request) {}
+			`)
+
+			var buf bytes.Buffer
+			So(r.WriteJSON(&buf), ShouldBeNil)
+			So(buf.String(), ShouldEqual, `{
+  "total": 1,
+  "documented": 0,
+  "coverage": 0,
+  "undocumented": [
+    {
+      "package": "main",
+      "name": "GetUser",
+      "location": "handlers.go:6:5"
+    }
+  ]
+}
+`)
+		})
+	})
+}
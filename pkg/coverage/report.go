@@ -0,0 +1,60 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type Report struct {
+	Handlers     []Handler
+	Documented   int
+	Undocumented []Handler
+}
+
+// Total returns the number of handlers found.
+func (r *Report) Total() int {
+	return len(r.Handlers)
+}
+
+// Coverage returns the percentage of documented handlers. A report without
+// handlers is considered fully covered.
+func (r *Report) Coverage() float64 {
+	if r.Total() == 0 {
+		return 100
+	}
+	return float64(r.Documented) * 100 / float64(r.Total())
+}
+
+func (r *Report) WriteText(w io.Writer) error {
+	for _, handler := range r.Undocumented {
+		_, err := fmt.Fprintf(w, "%v: %s.%s is not documented\n", handler.Position, handler.Package, handler.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "coverage: %.1f%% (%d/%d handlers documented)\n", r.Coverage(), r.Documented, r.Total())
+	return err
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	undocumented := r.Undocumented
+	if undocumented == nil {
+		undocumented = []Handler{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Total        int       `json:"total"`
+		Documented   int       `json:"documented"`
+		Coverage     float64   `json:"coverage"`
+		Undocumented []Handler `json:"undocumented"`
+	}{
+		Total:        r.Total(),
+		Documented:   r.Documented,
+		Coverage:     r.Coverage(),
+		Undocumented: undocumented,
+	})
+}
@@ -0,0 +1,151 @@
+package coverage
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DefaultSignature is the signature of net/http handler functions.
+const DefaultSignature = "func(http.ResponseWriter, *http.Request)"
+
+// Signature describes the parameter and result types of handler functions.
+// Package qualifiers are matched against the last element of the import
+// path, ignoring major version suffixes (e.g. echo.Context matches
+// github.com/labstack/echo/v4.Context).
+type Signature struct {
+	Params  []string
+	Results []string
+}
+
+func ParseSignature(str string) (Signature, error) {
+	expr, err := parser.ParseExpr(str)
+	if err != nil {
+		return Signature{}, fmt.Errorf("invalid handler signature %q: %v", str, err)
+	}
+	funcType, ok := expr.(*ast.FuncType)
+	if !ok {
+		return Signature{}, fmt.Errorf("invalid handler signature %q: must be a function type", str)
+	}
+
+	qualify := func(name string) string { return name }
+	return Signature{
+		Params:  fieldTypes(funcType.Params, qualify),
+		Results: fieldTypes(funcType.Results, qualify),
+	}, nil
+}
+
+func (sig Signature) String() string {
+	str := "func(" + strings.Join(sig.Params, ", ") + ")"
+	switch len(sig.Results) {
+	case 0:
+		return str
+	case 1:
+		return str + " " + sig.Results[0]
+	default:
+		return str + " (" + strings.Join(sig.Results, ", ") + ")"
+	}
+}
+
+func (sig Signature) match(params []string, results []string) bool {
+	return equalStrings(sig.Params, params) && equalStrings(sig.Results, results)
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldTypes(fields *ast.FieldList, qualify func(string) string) []string {
+	if fields == nil {
+		return nil
+	}
+
+	var types []string
+	for _, field := range fields.List {
+		typeName := typeString(field.Type, qualify)
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, typeName)
+		}
+	}
+	return types
+}
+
+func typeString(expr ast.Expr, qualify func(string) string) string {
+	switch typedExpr := expr.(type) {
+	case *ast.Ident:
+		return typedExpr.Name
+	case *ast.SelectorExpr:
+		if x, ok := typedExpr.X.(*ast.Ident); ok {
+			return qualify(x.Name) + "." + typedExpr.Sel.Name
+		}
+	case *ast.StarExpr:
+		return "*" + typeString(typedExpr.X, qualify)
+	case *ast.ArrayType:
+		if typedExpr.Len == nil {
+			return "[]" + typeString(typedExpr.Elt, qualify)
+		}
+	case *ast.MapType:
+		return "map[" + typeString(typedExpr.Key, qualify) + "]" + typeString(typedExpr.Value, qualify)
+	case *ast.Ellipsis:
+		return "..." + typeString(typedExpr.Elt, qualify)
+	case *ast.InterfaceType:
+		if typedExpr.Methods == nil || len(typedExpr.Methods.List) == 0 {
+			return "interface{}"
+		}
+	}
+	return fmt.Sprintf("<%T>", expr)
+}
+
+// importQualifiers returns the package qualifier used in signatures for each
+// import name in the file.
+func importQualifiers(file *ast.File) map[string]string {
+	qualifiers := map[string]string{}
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		qualifier := packageQualifier(importPath)
+
+		name := qualifier
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		qualifiers[name] = qualifier
+	}
+	return qualifiers
+}
+
+func packageQualifier(importPath string) string {
+	base := path.Base(importPath)
+	if len(base) > 1 && base[0] == 'v' {
+		if _, err := strconv.Atoi(base[1:]); err == nil {
+			base = path.Base(path.Dir(importPath))
+		}
+	}
+	// e.g. gopkg.in/yaml.v2
+	if i := strings.LastIndex(base, ".v"); i > 0 {
+		if _, err := strconv.Atoi(base[i+2:]); err == nil {
+			base = base[:i]
+		}
+	}
+	return base
+}
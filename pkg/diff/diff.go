@@ -0,0 +1,241 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// Change is a difference between two versions of a document.
+type Change struct {
+	// Operation is the affected operation, e.g. "GET /users".
+	Operation string `json:"operation,omitempty"`
+	// Pointer is the JSON pointer of the changed value, in the revised
+	// document, or the base document if removed.
+	Pointer  string `json:"pointer"`
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+func (c Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	if c.Operation != "" {
+		return fmt.Sprintf("%s: %s: %s", kind, c.Operation, c.Message)
+	}
+	return fmt.Sprintf("%s: %s", kind, c.Message)
+}
+
+// Compare compares the base and revised versions of generic OpenAPI
+// documents, as returned by openapi3.ToDocument or openapi3.ReadDocument.
+// Changes are classified as breaking if existing clients of the base version
+// may fail with the revised version.
+func Compare(base interface{}, revision interface{}) []Change {
+	c := &comparer{base: base, revision: revision}
+
+	basePaths := object(object(base)["paths"])
+	revisionPaths := object(object(revision)["paths"])
+	for _, path := range unionKeys(basePaths, revisionPaths) {
+		baseItem, revisionItem := object(basePaths[path]), object(revisionPaths[path])
+		for _, method := range openapi3.Methods {
+			key := strings.ToLower(method)
+			baseOp, inBase := baseItem[key].(map[string]interface{})
+			revisionOp, inRevision := revisionItem[key].(map[string]interface{})
+			operation := method + " " + path
+			pointer := openapi3.Pointer("paths", path, key)
+
+			switch {
+			case inBase && !inRevision:
+				c.report(operation, pointer, true, "operation removed")
+			case !inBase && inRevision:
+				c.report(operation, pointer, false, "operation added")
+			case inBase && inRevision:
+				c.operation = operation
+				c.compareOperation(pointer, baseItem, baseOp, revisionItem, revisionOp)
+			}
+		}
+	}
+
+	return c.changes
+}
+
+type comparer struct {
+	base      interface{}
+	revision  interface{}
+	operation string
+	changes   []Change
+	// visited records pairs of schema references being compared, to
+	// terminate comparison of recursive schemas.
+	visited map[[2]string]bool
+}
+
+func (c *comparer) report(operation string, pointer string, breaking bool, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		Operation: operation,
+		Pointer:   pointer,
+		Message:   fmt.Sprintf(format, args...),
+		Breaking:  breaking,
+	})
+}
+
+func (c *comparer) change(pointer string, breaking bool, format string, args ...interface{}) {
+	c.report(c.operation, pointer, breaking, format, args...)
+}
+
+// resolve resolves a reference object in the document.
+func resolve(doc interface{}, value interface{}) (map[string]interface{}, string) {
+	obj := object(value)
+	ref, ok := obj["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#") {
+		return obj, ""
+	}
+	target, err := jsonschema.ResolvePointer(doc, ref[1:])
+	if err != nil {
+		return nil, ref
+	}
+	return object(target), ref
+}
+
+func parameterKey(param map[string]interface{}) string {
+	return fmt.Sprintf("%v %v", param["in"], param["name"])
+}
+
+// parameters returns the parameters of an operation by their locations and
+// names, including parameters declared in the path item.
+func parameters(doc interface{}, pathItem map[string]interface{}, op map[string]interface{}) map[string]map[string]interface{} {
+	params := map[string]map[string]interface{}{}
+	for _, list := range []interface{}{pathItem["parameters"], op["parameters"]} {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			param, _ := resolve(doc, item)
+			if param != nil {
+				params[parameterKey(param)] = param
+			}
+		}
+	}
+	return params
+}
+
+func (c *comparer) compareOperation(pointer string, baseItem, baseOp, revisionItem, revisionOp map[string]interface{}) {
+	baseParams := parameters(c.base, baseItem, baseOp)
+	revisionParams := parameters(c.revision, revisionItem, revisionOp)
+	for _, key := range unionKeys(baseParams, revisionParams) {
+		baseParam, inBase := baseParams[key]
+		revisionParam, inRevision := revisionParams[key]
+		paramPointer := pointer + "/parameters"
+		switch {
+		case inBase && !inRevision:
+			c.change(paramPointer, true, "%s parameter %q removed", baseParam["in"], baseParam["name"])
+		case !inBase && inRevision:
+			required := isTrue(revisionParam["required"])
+			c.change(paramPointer, required, "%s parameter %q added%s", revisionParam["in"], revisionParam["name"], requiredSuffix(required))
+		default:
+			name := fmt.Sprintf("%s parameter %q", revisionParam["in"], revisionParam["name"])
+			if !isTrue(baseParam["required"]) && isTrue(revisionParam["required"]) {
+				c.change(paramPointer, true, "%s became required", name)
+			} else if isTrue(baseParam["required"]) && !isTrue(revisionParam["required"]) {
+				c.change(paramPointer, false, "%s became optional", name)
+			}
+			c.compareSchema(paramPointer, name, baseParam["schema"], revisionParam["schema"], request)
+		}
+	}
+
+	c.compareRequestBody(pointer+"/requestBody", baseOp["requestBody"], revisionOp["requestBody"])
+
+	baseResponses, _ := resolve(c.base, baseOp["responses"])
+	revisionResponses, _ := resolve(c.revision, revisionOp["responses"])
+	for _, statusCode := range unionKeys(baseResponses, revisionResponses) {
+		responsePointer := pointer + openapi3.Pointer("responses", statusCode)
+		baseResponse, inBase := baseResponses[statusCode]
+		revisionResponse, inRevision := revisionResponses[statusCode]
+		switch {
+		case inBase && !inRevision:
+			c.change(responsePointer, true, "response %s removed", statusCode)
+		case !inBase && inRevision:
+			c.change(responsePointer, false, "response %s added", statusCode)
+		default:
+			baseObj, _ := resolve(c.base, baseResponse)
+			revisionObj, _ := resolve(c.revision, revisionResponse)
+			c.compareContent(responsePointer, "response "+statusCode, baseObj["content"], revisionObj["content"], response)
+		}
+	}
+}
+
+func (c *comparer) compareRequestBody(pointer string, base, revision interface{}) {
+	baseBody, _ := resolve(c.base, base)
+	revisionBody, _ := resolve(c.revision, revision)
+	switch {
+	case base != nil && revision == nil:
+		c.change(pointer, false, "request body removed")
+	case base == nil && revision != nil:
+		required := isTrue(revisionBody["required"])
+		c.change(pointer, required, "request body added%s", requiredSuffix(required))
+	case base != nil && revision != nil:
+		if !isTrue(baseBody["required"]) && isTrue(revisionBody["required"]) {
+			c.change(pointer, true, "request body became required")
+		}
+		c.compareContent(pointer, "request body", baseBody["content"], revisionBody["content"], request)
+	}
+}
+
+func (c *comparer) compareContent(pointer string, name string, base, revision interface{}, dir direction) {
+	baseContent, revisionContent := object(base), object(revision)
+	for _, mediaType := range unionKeys(baseContent, revisionContent) {
+		mediaTypePointer := pointer + openapi3.Pointer("content", mediaType)
+		baseMediaType, inBase := baseContent[mediaType]
+		revisionMediaType, inRevision := revisionContent[mediaType]
+		switch {
+		case inBase && !inRevision:
+			c.change(mediaTypePointer, true, "%s media type %s removed", name, mediaType)
+		case !inBase && inRevision:
+			c.change(mediaTypePointer, false, "%s media type %s added", name, mediaType)
+		default:
+			c.compareSchema(mediaTypePointer+"/schema", name, object(baseMediaType)["schema"], object(revisionMediaType)["schema"], dir)
+		}
+	}
+}
+
+func isTrue(value interface{}) bool {
+	b, _ := value.(bool)
+	return b
+}
+
+func requiredSuffix(required bool) string {
+	if required {
+		return " as required"
+	}
+	return ""
+}
+
+func object(value interface{}) map[string]interface{} {
+	obj, _ := value.(map[string]interface{})
+	return obj
+}
+
+// unionKeys returns the sorted union of keys of the maps.
+func unionKeys(a, b interface{}) []string {
+	keySet := map[string]bool{}
+	for _, m := range []interface{}{a, b} {
+		switch m := m.(type) {
+		case map[string]interface{}:
+			for key := range m {
+				keySet[key] = true
+			}
+		case map[string]map[string]interface{}:
+			for key := range m {
+				keySet[key] = true
+			}
+		}
+	}
+	var keys []string
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,204 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const baseDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+paths:
+  /users:
+    get:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer}}
+      - {name: sort, in: query, schema: {type: string, enum: [name, created_at]}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/User'}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/User'}
+      responses:
+        "201": {description: Created}
+        "409": {description: Conflict}
+  /users/{id}:
+    delete:
+      responses:
+        "204": {description: Deleted}
+components:
+  schemas:
+    User:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        email: {type: string}
+        manager: {$ref: '#/components/schemas/User'}
+`
+
+const revisedDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 2.0.0}
+paths:
+  /users:
+    get:
+      parameters:
+      - {name: limit, in: query, schema: {type: string}}
+      - {name: sort, in: query, schema: {type: string, enum: [name]}}
+      - {name: cursor, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/User'}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/User'}
+      responses:
+        "201": {description: Created}
+        "400": {description: Bad Request}
+  /groups:
+    get:
+      responses:
+        "200": {description: OK}
+components:
+  schemas:
+    User:
+      type: object
+      required: [name, email]
+      properties:
+        name: {type: string}
+        email: {type: string}
+        manager: {$ref: '#/components/schemas/User'}
+        status: {type: string, enum: [active, disabled]}
+`
+
+func TestCompare(t *testing.T) {
+	Convey("Compare", t, func() {
+		base, err := openapi3.ReadDocument([]byte(baseDocument))
+		So(err, ShouldBeNil)
+		revision, err := openapi3.ReadDocument([]byte(revisedDocument))
+		So(err, ShouldBeNil)
+
+		changes := Compare(base, revision)
+		var lines []string
+		for _, change := range changes {
+			lines = append(lines, change.String())
+		}
+
+		So(lines, ShouldResemble, []string{
+			"non-breaking: GET /groups: operation added",
+			"non-breaking: GET /users: query parameter \"cursor\" added",
+			"breaking: GET /users: query parameter \"limit\" type changed from integer to string",
+			"breaking: GET /users: query parameter \"sort\" enum values removed: [\"created_at\"]",
+			"non-breaking: GET /users: response 200 items property \"email\" became required",
+			"non-breaking: GET /users: response 200 items property \"status\" added",
+			"breaking: POST /users: request body property \"email\" became required",
+			"non-breaking: POST /users: request body property \"status\" added",
+			"non-breaking: POST /users: response 400 added",
+			"breaking: POST /users: response 409 removed",
+			"breaking: DELETE /users/{id}: operation removed",
+		})
+		So(changes[2].Pointer, ShouldEqual, "/paths/~1users/get/parameters")
+		So(changes[6].Pointer, ShouldEqual, "/paths/~1users/post/requestBody/content/application~1json/schema/properties/email")
+
+		Convey("should report unchanged documents", func() {
+			So(Compare(base, base), ShouldBeEmpty)
+		})
+
+		Convey("should compare schemas shared by requests and responses", func() {
+			const shared = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+%s`
+			base, err := openapi3.ReadDocument([]byte(fmt.Sprintf(shared, "        age: {type: integer}\n")))
+			So(err, ShouldBeNil)
+			revision, err := openapi3.ReadDocument([]byte(fmt.Sprintf(shared, "")))
+			So(err, ShouldBeNil)
+
+			var lines []string
+			for _, change := range Compare(base, revision) {
+				lines = append(lines, change.String())
+			}
+			So(lines, ShouldResemble, []string{
+				"non-breaking: POST /pets: request body property \"age\" removed",
+				"breaking: POST /pets: response 200 property \"age\" removed",
+			})
+		})
+
+		Convey("should write reports", func() {
+			report := &Report{Changes: changes}
+			So(report.Breaking(), ShouldHaveLength, 5)
+			So(report.NonBreaking(), ShouldHaveLength, 6)
+
+			var buf bytes.Buffer
+			So(report.WriteMarkdown(&buf), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, "## Breaking changes\n\n- `GET /users`: query parameter \"limit\" type changed from integer to string\n")
+
+			buf.Reset()
+			So(report.WriteJSON(&buf), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, `"breaking": 5,`)
+		})
+	})
+}
+
+func TestUnified(t *testing.T) {
+	Convey("Unified", t, func() {
+		So(Unified("a", "b", "x\ny\n", "x\ny\n"), ShouldEqual, "")
+
+		a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+		b := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n"
+		So(Unified("a", "b", a, b), ShouldEqual, `--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+@@ -13,3 +13,4 @@
+ 13
+ 14
+ 15
++16
+`)
+
+		So(Unified("a", "b", "", "x\n"), ShouldEqual, "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n")
+	})
+}
@@ -0,0 +1,90 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Report struct {
+	Changes []Change
+}
+
+// Breaking returns the breaking changes.
+func (r *Report) Breaking() []Change {
+	return r.filter(true)
+}
+
+// NonBreaking returns the non-breaking changes.
+func (r *Report) NonBreaking() []Change {
+	return r.filter(false)
+}
+
+func (r *Report) filter(breaking bool) (changes []Change) {
+	for _, change := range r.Changes {
+		if change.Breaking == breaking {
+			changes = append(changes, change)
+		}
+	}
+	return
+}
+
+func (r *Report) WriteText(w io.Writer) error {
+	for _, change := range r.Changes {
+		_, err := fmt.Fprintln(w, change)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d breaking, %d non-breaking changes\n", len(r.Breaking()), len(r.NonBreaking()))
+	return err
+}
+
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# API changes\n")
+	for _, section := range []struct {
+		title   string
+		changes []Change
+	}{
+		{"Breaking changes", r.Breaking()},
+		{"Non-breaking changes", r.NonBreaking()},
+	} {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		if len(section.changes) == 0 {
+			b.WriteString("None.\n")
+			continue
+		}
+		for _, change := range section.changes {
+			if change.Operation != "" {
+				fmt.Fprintf(&b, "- `%s`: %s\n", change.Operation, change.Message)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", change.Message)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	changes := r.Changes
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Breaking    int      `json:"breaking"`
+		NonBreaking int      `json:"nonBreaking"`
+		Changes     []Change `json:"changes"`
+	}{
+		Breaking:    len(r.Breaking()),
+		NonBreaking: len(r.NonBreaking()),
+		Changes:     changes,
+	})
+}
@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// direction is the direction of data described by a schema. Narrowing
+// accepted values breaks clients sending requests, while widening returned
+// values breaks clients reading responses.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+func (c *comparer) compareSchema(pointer string, name string, base, revision interface{}, dir direction) {
+	if base == nil || revision == nil {
+		if base != nil || revision != nil {
+			c.change(pointer, true, "%s schema changed", name)
+		}
+		return
+	}
+
+	baseSchema, baseRef := resolve(c.base, base)
+	revisionSchema, revisionRef := resolve(c.revision, revision)
+	// An inline schema is finite, so recursion terminates unless both
+	// schemas are references. Only references being compared further up
+	// are skipped, so a schema shared by a request and a response is
+	// compared in both directions.
+	if baseRef != "" && revisionRef != "" {
+		key := [2]string{baseRef, revisionRef}
+		if c.visited == nil {
+			c.visited = map[[2]string]bool{}
+		}
+		if c.visited[key] {
+			return
+		}
+		c.visited[key] = true
+		defer delete(c.visited, key)
+	}
+
+	if baseType, revisionType := baseSchema["type"], revisionSchema["type"]; !jsonschema.Equal(baseType, revisionType) {
+		c.change(pointer, true, "%s type changed from %s to %s", name, typeName(baseType), typeName(revisionType))
+		return
+	}
+	if baseFormat, revisionFormat := baseSchema["format"], revisionSchema["format"]; !jsonschema.Equal(baseFormat, revisionFormat) {
+		c.change(pointer, true, "%s format changed from %s to %s", name, typeName(baseFormat), typeName(revisionFormat))
+	}
+
+	c.compareEnum(pointer, name, baseSchema["enum"], revisionSchema["enum"], dir)
+
+	if baseSchema["items"] != nil || revisionSchema["items"] != nil {
+		c.compareSchema(pointer+"/items", name+" items", baseSchema["items"], revisionSchema["items"], dir)
+	}
+
+	baseProps, revisionProps := object(baseSchema["properties"]), object(revisionSchema["properties"])
+	baseRequired, revisionRequired := stringSet(baseSchema["required"]), stringSet(revisionSchema["required"])
+	for _, prop := range unionKeys(baseProps, revisionProps) {
+		propPointer := pointer + openapi3.Pointer("properties", prop)
+		propName := fmt.Sprintf("%s property %q", name, prop)
+		baseProp, inBase := baseProps[prop]
+		revisionProp, inRevision := revisionProps[prop]
+		switch {
+		case inBase && !inRevision:
+			// Clients may read removed response properties; removed request
+			// properties are ignored.
+			c.change(propPointer, dir == response, "%s removed", propName)
+		case !inBase && inRevision:
+			required := revisionRequired[prop]
+			c.change(propPointer, dir == request && required, "%s added%s", propName, requiredSuffix(required))
+		default:
+			if !baseRequired[prop] && revisionRequired[prop] {
+				c.change(propPointer, dir == request, "%s became required", propName)
+			} else if baseRequired[prop] && !revisionRequired[prop] {
+				c.change(propPointer, dir == response, "%s became optional", propName)
+			}
+			c.compareSchema(propPointer, propName, baseProp, revisionProp, dir)
+		}
+	}
+}
+
+func (c *comparer) compareEnum(pointer string, name string, base, revision interface{}, dir direction) {
+	baseValues, _ := base.([]interface{})
+	revisionValues, _ := revision.([]interface{})
+
+	switch {
+	case base == nil && revision == nil:
+		return
+	case base == nil:
+		c.change(pointer, dir == request, "%s restricted to enum %s", name, jsonString(revisionValues))
+		return
+	case revision == nil:
+		c.change(pointer, dir == response, "%s enum removed", name)
+		return
+	}
+
+	if removed := difference(baseValues, revisionValues); len(removed) > 0 {
+		c.change(pointer, dir == request, "%s enum values removed: %s", name, jsonString(removed))
+	}
+	if added := difference(revisionValues, baseValues); len(added) > 0 {
+		c.change(pointer, dir == response, "%s enum values added: %s", name, jsonString(added))
+	}
+}
+
+// difference returns values in a but not in b.
+func difference(a, b []interface{}) (values []interface{}) {
+	for _, value := range a {
+		found := false
+		for _, other := range b {
+			if jsonschema.Equal(value, other) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, value)
+		}
+	}
+	return
+}
+
+func stringSet(value interface{}) map[string]bool {
+	set := map[string]bool{}
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		if str, ok := item.(string); ok {
+			set[str] = true
+		}
+	}
+	return set
+}
+
+func typeName(value interface{}) string {
+	if value == nil {
+		return "unspecified"
+	}
+	return jsonString(value)
+}
+
+func jsonString(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
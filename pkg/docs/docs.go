@@ -0,0 +1,550 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// Site is the view of a document rendered as documentation. Operations are
+// grouped by tag; untagged operations are in a tag named "default".
+type Site struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []Server
+	Security    []SecurityScheme
+	Tags        []*Tag
+}
+
+type Server struct {
+	URL         string
+	Description string
+	Variables   []ServerVariable
+}
+
+type ServerVariable struct {
+	Name        string
+	Default     string
+	Description string
+	Enum        []string
+}
+
+type SecurityScheme struct {
+	Name        string
+	Description string
+	// Kind describes the scheme, e.g. "API key in header X-API-Key".
+	Kind string
+}
+
+type Tag struct {
+	Name        string
+	Description string
+	// Anchor is the stable anchor of the tag.
+	Anchor     string
+	Operations []*Operation
+}
+
+type Operation struct {
+	// Anchor is the stable anchor of the operation, derived from its ID, or
+	// its method and path.
+	Anchor      string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Parameters  []Parameter
+	RequestBody *Body
+	Responses   []Response
+	// Security is the alternatives of security schemes required, e.g.
+	// [["api_key"], ["access_token"]].
+	Security [][]string
+}
+
+type Parameter struct {
+	Name        string
+	Location    string
+	Description string
+	Required    bool
+	Deprecated  bool
+	Type        string
+	Constraints string
+}
+
+type Body struct {
+	Description string
+	Required    bool
+	Content     []Content
+}
+
+type Response struct {
+	Code        string
+	Description string
+	Content     []Content
+}
+
+// Content is the schema and examples of a media type.
+type Content struct {
+	MediaType string
+	// Type is the type of the schema, e.g. "User" or "array of User".
+	Type string
+	// Fields are the properties of the schema expanded from references.
+	Fields   []Field
+	Examples []Example
+}
+
+// Field is a property of a schema. Properties of nested objects and arrays
+// of objects follow their parent field, e.g. "address.city" and
+// "items[].name".
+type Field struct {
+	Path        string
+	Name        string
+	Depth       int
+	Type        string
+	Required    bool
+	Description string
+	Constraints string
+}
+
+type Example struct {
+	Name        string
+	Summary     string
+	Description string
+	// JSON is the indented JSON of the example value.
+	JSON string
+}
+
+// DefaultTag is the tag of untagged operations.
+const DefaultTag = "default"
+
+// NewSite returns the view of the document.
+func NewSite(oapi *openapi3.OpenAPIObject) *Site {
+	site := &Site{
+		Title:       oapi.Info.Title,
+		Version:     oapi.Info.Version,
+		Description: oapi.Info.Description,
+	}
+
+	for _, server := range oapi.Servers {
+		s := Server{URL: server.URL, Description: server.Description}
+		for _, name := range openapi3.SortedKeys(server.Variables) {
+			variable := server.Variables[name]
+			s.Variables = append(s.Variables, ServerVariable{
+				Name:        name,
+				Default:     variable.Default,
+				Description: variable.Description,
+				Enum:        variable.Enum,
+			})
+		}
+		site.Servers = append(site.Servers, s)
+	}
+
+	for _, name := range openapi3.SortedKeys(oapi.Components.SecuritySchemes) {
+		scheme := oapi.Components.SecuritySchemes[name]
+		site.Security = append(site.Security, SecurityScheme{
+			Name:        name,
+			Description: scheme.Description,
+			Kind:        securitySchemeKind(scheme),
+		})
+	}
+
+	tags := map[string]*Tag{}
+	tag := func(name string) *Tag {
+		if t, ok := tags[name]; ok {
+			return t
+		}
+		t := &Tag{Name: name, Anchor: "tag-" + slug(name)}
+		tags[name] = t
+		site.Tags = append(site.Tags, t)
+		return t
+	}
+	for _, t := range oapi.Tags {
+		tag(t.Name).Description = t.Description
+	}
+
+	b := &builder{oapi: oapi, anchors: map[string]bool{}}
+	for _, path := range openapi3.SortedKeys(oapi.Paths) {
+		item := oapi.Paths[path]
+		for _, method := range openapi3.Methods {
+			op := item.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			operation := b.operation(method, path, op)
+			if len(op.Tags) == 0 {
+				tag(DefaultTag).Operations = append(tag(DefaultTag).Operations, operation)
+			}
+			for _, name := range op.Tags {
+				tag(name).Operations = append(tag(name).Operations, operation)
+			}
+		}
+	}
+
+	// Declared tags without operations are omitted.
+	var nonEmpty []*Tag
+	for _, t := range site.Tags {
+		if len(t.Operations) > 0 {
+			nonEmpty = append(nonEmpty, t)
+		}
+	}
+	site.Tags = nonEmpty
+	return site
+}
+
+// Operations returns the operations of the site in order of tags. Operations
+// with multiple tags are returned once.
+func (site *Site) Operations() []*Operation {
+	var operations []*Operation
+	seen := map[*Operation]bool{}
+	for _, tag := range site.Tags {
+		for _, op := range tag.Operations {
+			if !seen[op] {
+				seen[op] = true
+				operations = append(operations, op)
+			}
+		}
+	}
+	return operations
+}
+
+func securitySchemeKind(scheme *openapi3.SecuritySchemeObject) string {
+	switch scheme.Type {
+	case openapi3.SecuritySchemeTypeAPIKey:
+		return fmt.Sprintf("API key in %s %s", scheme.APIKeyLocation, scheme.APIKeyName)
+	case openapi3.SecuritySchemeTypeHTTP:
+		kind := "HTTP " + strings.ToLower(scheme.HTTPAuthScheme) + " authentication"
+		if scheme.HTTPBearerFormat != "" {
+			kind += " (" + scheme.HTTPBearerFormat + ")"
+		}
+		return kind
+	default:
+		return string(scheme.Type)
+	}
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug converts a name to an anchor, e.g. "GET /users/{id}" -> "get-users-id".
+func slug(name string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+type builder struct {
+	oapi    *openapi3.OpenAPIObject
+	anchors map[string]bool
+}
+
+// anchor returns a unique anchor of the name.
+func (b *builder) anchor(name string) string {
+	anchor := slug(name)
+	unique := anchor
+	for i := 2; b.anchors[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", anchor, i)
+	}
+	b.anchors[unique] = true
+	return unique
+}
+
+func (b *builder) operation(method string, path string, op *openapi3.OperationObject) *Operation {
+	name := op.ID
+	if name == "" {
+		name = method + " " + path
+	}
+	operation := &Operation{
+		Anchor:      b.anchor(name),
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+	}
+
+	item := b.oapi.Paths[path]
+	for i := range item.Parameters {
+		operation.Parameters = append(operation.Parameters, b.parameter(&item.Parameters[i]))
+	}
+	for _, param := range op.Parameters {
+		if paramObj := b.oapi.ResolveParameter(param); paramObj != nil {
+			operation.Parameters = append(operation.Parameters, b.parameter(paramObj))
+		}
+	}
+
+	if body := b.oapi.ResolveRequestBody(op.RequestBody); body != nil {
+		operation.RequestBody = &Body{
+			Description: body.Description,
+			Required:    body.Required,
+			Content:     b.content(body.Content),
+		}
+	}
+
+	for _, code := range openapi3.SortedKeys(op.Responses) {
+		response := b.oapi.ResolveResponse(op.Responses[code])
+		if response == nil {
+			continue
+		}
+		operation.Responses = append(operation.Responses, Response{
+			Code:        code,
+			Description: response.Description,
+			Content:     b.content(response.Content),
+		})
+	}
+
+	security := op.Security
+	if security == nil {
+		security = b.oapi.Security
+	}
+	for _, requirement := range security {
+		operation.Security = append(operation.Security, openapi3.SortedKeys(requirement))
+	}
+	return operation
+}
+
+func (b *builder) parameter(param *openapi3.ParameterObject) Parameter {
+	schema, _ := b.oapi.ResolveSchema(param.Schema)
+	return Parameter{
+		Name:        param.Name,
+		Location:    string(param.Location),
+		Description: param.Description,
+		Required:    param.Required,
+		Deprecated:  param.Deprecated,
+		Type:        b.typeName(param.Schema),
+		Constraints: constraints(schema),
+	}
+}
+
+func (b *builder) content(content map[string]openapi3.MediaTypeObject) []Content {
+	var result []Content
+	for _, mediaType := range openapi3.SortedKeys(content) {
+		mt := content[mediaType]
+		c := Content{MediaType: mediaType}
+		if mt.Schema != nil {
+			c.Type = b.typeName(mt.Schema)
+			c.Fields = b.fields(mt.Schema, "", 0, nil)
+		}
+		for _, name := range openapi3.SortedKeys(mt.Examples) {
+			example := mt.Examples[name]
+			c.Examples = append(c.Examples, Example{
+				Name:        name,
+				Summary:     example.Summary,
+				Description: example.Description,
+				JSON:        indentJSON(example.Value),
+			})
+		}
+		if len(c.Examples) == 0 && mt.Example != nil {
+			c.Examples = append(c.Examples, Example{Name: "example", JSON: indentJSON(mt.Example)})
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+func indentJSON(value interface{}) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// resolve resolves the schema if it is a reference, returning the ID of the
+// referenced component.
+func (b *builder) resolve(schema interface{}) (map[string]interface{}, string) {
+	return b.oapi.ResolveSchema(schema)
+}
+
+// typeName returns the type of the schema, e.g. "string", "User" or "array
+// of User".
+func (b *builder) typeName(schema interface{}) string {
+	obj, id := b.resolve(schema)
+	if id != "" {
+		return id
+	}
+	if obj == nil {
+		return "any"
+	}
+
+	var name string
+	var types []string
+	switch t := obj["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, value := range t {
+			types = append(types, fmt.Sprint(value))
+		}
+	}
+	for i, t := range types {
+		if t == "array" {
+			types[i] = "array of " + b.typeName(obj["items"])
+		}
+	}
+	switch {
+	case len(types) > 0:
+		name = strings.Join(types, " | ")
+	case obj["oneOf"] != nil:
+		name = "one of " + b.typeNames(obj["oneOf"])
+	case obj["anyOf"] != nil:
+		name = "any of " + b.typeNames(obj["anyOf"])
+	case obj["allOf"] != nil || obj["properties"] != nil:
+		name = "object"
+	default:
+		name = "any"
+	}
+	if obj["nullable"] == true {
+		name += " | null"
+	}
+	return name
+}
+
+func (b *builder) typeNames(schemas interface{}) string {
+	values, _ := schemas.([]interface{})
+	var names []string
+	for _, value := range values {
+		names = append(names, b.typeName(value))
+	}
+	return strings.Join(names, ", ")
+}
+
+// fields returns the properties of the schema, expanding references and
+// allOf. References already being expanded are not expanded again.
+func (b *builder) fields(schema interface{}, prefix string, depth int, expanding []string) []Field {
+	obj, id := b.resolve(schema)
+	if obj == nil {
+		return nil
+	}
+	if id != "" {
+		for _, expandingID := range expanding {
+			if expandingID == id {
+				return nil
+			}
+		}
+		expanding = append(expanding, id)
+	}
+
+	if items, ok := obj["items"]; ok {
+		return b.fields(items, prefix+"[]", depth, expanding)
+	}
+
+	properties, required := b.properties(obj, expanding)
+	var fields []Field
+	for _, name := range openapi3.SortedKeys(properties) {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		property := properties[name]
+		resolved, _ := b.resolve(property)
+		description, _ := resolved["description"].(string)
+		if local, ok := property.(map[string]interface{}); ok {
+			if d, ok := local["description"].(string); ok {
+				description = d
+			}
+		}
+		fields = append(fields, Field{
+			Path:        path,
+			Name:        name,
+			Depth:       depth,
+			Type:        b.typeName(property),
+			Required:    required[name],
+			Description: description,
+			Constraints: constraints(resolved),
+		})
+		fields = append(fields, b.fields(property, path, depth+1, expanding)...)
+	}
+	return fields
+}
+
+// properties returns the properties of an object schema, including the
+// properties of allOf subschemas, and whether they are required.
+func (b *builder) properties(obj map[string]interface{}, expanding []string) (map[string]interface{}, map[string]bool) {
+	properties := map[string]interface{}{}
+	required := map[string]bool{}
+	if values, ok := obj["properties"].(map[string]interface{}); ok {
+		for name, value := range values {
+			properties[name] = value
+		}
+	}
+	if values, ok := obj["required"].([]interface{}); ok {
+		for _, value := range values {
+			if name, ok := value.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	subschemas, _ := obj["allOf"].([]interface{})
+	for _, subschema := range subschemas {
+		resolved, id := b.resolve(subschema)
+		if resolved == nil || containsString(expanding, id) {
+			continue
+		}
+		if id != "" {
+			expanding = append(expanding, id)
+		}
+		subProperties, subRequired := b.properties(resolved, expanding)
+		for name, value := range subProperties {
+			if _, exists := properties[name]; !exists {
+				properties[name] = value
+			}
+		}
+		for name := range subRequired {
+			required[name] = true
+		}
+	}
+	return properties, required
+}
+
+// constraintKeywords are the keywords described in constraints, in order.
+var constraintKeywords = []string{
+	"format", "enum", "const", "default", "minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum",
+	"minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems",
+}
+
+// constraints describes the validation keywords of the schema, e.g.
+// "format: email, maxLength: 100".
+func constraints(schema map[string]interface{}) string {
+	var parts []string
+	for _, keyword := range constraintKeywords {
+		value, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+		if values, ok := value.([]interface{}); ok {
+			var strs []string
+			for _, v := range values {
+				strs = append(strs, formatValue(v))
+			}
+			parts = append(parts, keyword+": "+strings.Join(strs, ", "))
+			continue
+		}
+		parts = append(parts, keyword+": "+formatValue(value))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func formatValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func containsString(values []string, str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, value := range values {
+		if value == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package docs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0, description: API for testing}
+servers:
+- url: 'https://{env}.example.com/'
+  variables:
+    env: {default: api, enum: [api, staging], description: Environment}
+security:
+- api_key: []
+tags:
+- {name: User, description: User APIs}
+- {name: Unused}
+paths:
+  /users/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: string, format: uuid}}
+    get:
+      tags: [User]
+      summary: Get user
+      operationId: getUser
+      responses:
+        "200":
+          description: User
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+              examples:
+                user: {summary: A user, value: {name: Test}}
+        "404": {$ref: '#/components/responses/NotFound'}
+  /health:
+    get:
+      summary: Check health
+      security: []
+      responses:
+        "204": {description: Healthy}
+components:
+  schemas:
+    Base:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string, description: ID of the object}
+    User:
+      allOf:
+      - $ref: '#/components/schemas/Base'
+      - type: object
+        required: [name]
+        properties:
+          name: {type: string, maxLength: 100}
+          friends: {type: array, items: {$ref: '#/components/schemas/User'}}
+          address:
+            type: object
+            properties:
+              city: {type: string, nullable: true}
+  responses:
+    NotFound: {description: Not found}
+  securitySchemes:
+    api_key: {type: apiKey, name: X-API-Key, in: header, description: API key}
+`
+
+func TestNewSite(t *testing.T) {
+	Convey("NewSite", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+		site := NewSite(oapi)
+
+		So(site.Title, ShouldEqual, "Test API")
+		So(site.Servers[0].Variables, ShouldResemble, []ServerVariable{
+			{Name: "env", Default: "api", Description: "Environment", Enum: []string{"api", "staging"}},
+		})
+		So(site.Security, ShouldResemble, []SecurityScheme{
+			{Name: "api_key", Description: "API key", Kind: "API key in header X-API-Key"},
+		})
+
+		So(site.Tags, ShouldHaveLength, 2)
+		So(site.Tags[0].Name, ShouldEqual, "User")
+		So(site.Tags[0].Anchor, ShouldEqual, "tag-user")
+		So(site.Tags[1].Name, ShouldEqual, DefaultTag)
+		So(site.Tags[1].Operations[0].Anchor, ShouldEqual, "get-health")
+		So(site.Tags[1].Operations[0].Security, ShouldBeEmpty)
+
+		op := site.Tags[0].Operations[0]
+		So(op.Anchor, ShouldEqual, "getuser")
+		So(op.Security, ShouldResemble, [][]string{{"api_key"}})
+		So(op.Parameters, ShouldResemble, []Parameter{
+			{Name: "id", Location: "path", Required: true, Type: "string", Constraints: "format: uuid"},
+		})
+		So(op.Responses, ShouldHaveLength, 2)
+		So(op.Responses[1], ShouldResemble, Response{Code: "404", Description: "Not found"})
+
+		content := op.Responses[0].Content[0]
+		So(content.Type, ShouldEqual, "User")
+		So(content.Fields, ShouldResemble, []Field{
+			{Path: "address", Name: "address", Type: "object"},
+			{Path: "address.city", Name: "city", Depth: 1, Type: "string | null"},
+			{Path: "friends", Name: "friends", Type: "array of User"},
+			{Path: "id", Name: "id", Type: "string", Required: true, Description: "ID of the object"},
+			{Path: "name", Name: "name", Type: "string", Required: true, Constraints: "maxLength: 100"},
+		})
+		So(content.Examples, ShouldResemble, []Example{
+			{Name: "user", Summary: "A user", JSON: "{\n  \"name\": \"Test\"\n}"},
+		})
+	})
+}
+
+func TestRenderHTML(t *testing.T) {
+	Convey("RenderHTML", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+
+		Convey("should render pages", func() {
+			files, err := RenderHTML(oapi, "")
+			So(err, ShouldBeNil)
+			So(files, ShouldContainKey, "index.html")
+			So(files, ShouldContainKey, "style.css")
+			So(files, ShouldContainKey, "operations/getuser.html")
+			So(files, ShouldContainKey, "operations/get-health.html")
+
+			index := string(files["index.html"])
+			So(index, ShouldContainSubstring, `<code>https://{env}.example.com/</code>`)
+			So(index, ShouldContainSubstring, `<a href="operations/getuser.html"><code>/users/{id}</code></a>`)
+			So(index, ShouldNotContainSubstring, "Unused")
+
+			page := string(files["operations/getuser.html"])
+			So(page, ShouldContainSubstring, `<link rel="stylesheet" href="../style.css">`)
+			So(page, ShouldContainSubstring, `<code>api_key</code>`)
+			So(page, ShouldContainSubstring, `<td style="padding-left: 1.5em"><code>city</code></td>`)
+			So(page, ShouldContainSubstring, "&#34;name&#34;: &#34;Test&#34;")
+			So(page, ShouldNotContainSubstring, "https://")
+		})
+
+		Convey("should use templates in template directory", func() {
+			dir, err := ioutil.TempDir("", "templates")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+			err = ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte(`{{define "content"}}Custom {{.Site.Title}}{{end}}`), 0644)
+			So(err, ShouldBeNil)
+
+			files, err := RenderHTML(oapi, dir)
+			So(err, ShouldBeNil)
+			So(string(files["index.html"]), ShouldContainSubstring, "<main>\nCustom Test API\n</main>")
+			So(string(files["operations/getuser.html"]), ShouldContainSubstring, "Get user")
+		})
+	})
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	Convey("RenderMarkdown", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+
+		Convey("should render single file", func() {
+			data, err := RenderMarkdown(oapi, "")
+			So(err, ShouldBeNil)
+			doc := string(data)
+			So(doc, ShouldStartWith, "# Test API\n")
+			So(doc, ShouldContainSubstring, "- [User](#tag-user)\n- [default](#tag-default)\n")
+			So(doc, ShouldContainSubstring, "| GET | [`/users/{id}`](#getuser) | Get user |\n")
+			So(doc, ShouldContainSubstring, "<a id=\"getuser\"></a>\n### Get user\n")
+			So(doc, ShouldContainSubstring, "| `id` | path | string<br>format: uuid | yes |  |\n")
+			So(doc, ShouldContainSubstring, "| 404 | Not found |\n")
+			So(doc, ShouldContainSubstring, "| `address.city` | string \\| null |  |  |\n")
+			So(doc, ShouldContainSubstring, "```json\n{\n  \"name\": \"Test\"\n}\n```\n")
+		})
+
+		Convey("should render file of each tag", func() {
+			files, err := RenderMarkdownTags(oapi, "")
+			So(err, ShouldBeNil)
+			So(files, ShouldHaveLength, 3)
+			So(string(files["index.md"]), ShouldContainSubstring, "| [User](user.md) | User APIs |\n")
+			So(string(files["user.md"]), ShouldContainSubstring, "<a id=\"getuser\"></a>")
+			So(string(files["user.md"]), ShouldNotContainSubstring, "/health")
+			So(string(files["default.md"]), ShouldContainSubstring, "<a id=\"get-health\"></a>")
+		})
+	})
+}
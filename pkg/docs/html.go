@@ -0,0 +1,101 @@
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// htmlTemplateFiles are the files of HTML templates. Templates of pages
+// define the "content" template executed by the layout.
+var htmlTemplateFiles = []string{"layout.html", "index.html", "operation.html", "style.css"}
+
+// htmlPage is the data of HTML templates.
+type htmlPage struct {
+	Site      *Site
+	Operation *Operation
+	// Root is the relative path of the site root from the page.
+	Root string
+}
+
+var htmlFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// RenderHTML renders the document as a static site, returning the contents
+// of its files by relative path: index.html, style.css, and a page of each
+// operation in the operations directory. Files in the template directory, if
+// specified, replace the built-in templates with the same name.
+func RenderHTML(oapi *openapi3.OpenAPIObject, templateDir string) (map[string][]byte, error) {
+	files, err := loadTemplates("html", htmlTemplateFiles, templateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	site := NewSite(oapi)
+	output := map[string][]byte{
+		"style.css": files["style.css"],
+	}
+
+	render := func(name string, page htmlPage) ([]byte, error) {
+		t, err := template.New("layout.html").Funcs(htmlFuncs).Parse(string(files["layout.html"]))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse template layout.html")
+		}
+		if _, err := t.New(name).Parse(string(files[name])); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse template %s", name)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, page); err != nil {
+			return nil, errors.Wrapf(err, "failed to render %s", name)
+		}
+		return buf.Bytes(), nil
+	}
+
+	output["index.html"], err = render("index.html", htmlPage{Site: site})
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range site.Operations() {
+		data, err := render("operation.html", htmlPage{Site: site, Operation: op, Root: "../"})
+		if err != nil {
+			return nil, err
+		}
+		output[path.Join("operations", op.Anchor+".html")] = data
+	}
+	return output, nil
+}
+
+// loadTemplates returns the built-in template files of the kind, replaced by
+// the files in the template directory if they exist.
+func loadTemplates(kind string, names []string, templateDir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, name := range names {
+		data, err := templates.ReadFile(path.Join("templates", kind, name))
+		if err != nil {
+			return nil, err
+		}
+		if templateDir != "" {
+			override, err := ioutil.ReadFile(filepath.Join(templateDir, name))
+			if err == nil {
+				data = override
+			} else if !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+		files[name] = data
+	}
+	return files, nil
+}
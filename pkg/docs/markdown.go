@@ -0,0 +1,90 @@
+package docs
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// markdownTemplateFiles are the files of Markdown templates.
+var markdownTemplateFiles = []string{"markdown.md"}
+
+// markdownPage is the data of Markdown templates.
+type markdownPage struct {
+	Site *Site
+	Tag  *Tag
+}
+
+var markdownFuncs = template.FuncMap{
+	"join": strings.Join,
+	"cell": markdownCell,
+	"file": tagFile,
+}
+
+// markdownCell escapes text in a table cell.
+func markdownCell(text string) string {
+	text = strings.Replace(text, "|", `\|`, -1)
+	return strings.Replace(strings.TrimSpace(text), "\n", "<br>", -1)
+}
+
+// tagFile returns the name of the Markdown file of a tag.
+func tagFile(tag *Tag) string {
+	return strings.TrimPrefix(tag.Anchor, "tag-") + ".md"
+}
+
+// RenderMarkdown renders the document as a single Markdown file. Tags and
+// operations are preceded by HTML anchors with the names of Tag.Anchor and
+// Operation.Anchor.
+func RenderMarkdown(oapi *openapi3.OpenAPIObject, templateDir string) ([]byte, error) {
+	t, err := loadMarkdownTemplates(templateDir)
+	if err != nil {
+		return nil, err
+	}
+	return renderMarkdown(t, "document", markdownPage{Site: NewSite(oapi)})
+}
+
+// RenderMarkdownTags renders the document as Markdown files by relative path:
+// index.md, and a file of each tag named after its anchor, e.g. user.md.
+func RenderMarkdownTags(oapi *openapi3.OpenAPIObject, templateDir string) (map[string][]byte, error) {
+	t, err := loadMarkdownTemplates(templateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	site := NewSite(oapi)
+	output := map[string][]byte{}
+	output["index.md"], err = renderMarkdown(t, "index", markdownPage{Site: site})
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range site.Tags {
+		output[tagFile(tag)], err = renderMarkdown(t, "tag-file", markdownPage{Site: site, Tag: tag})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+func loadMarkdownTemplates(templateDir string) (*template.Template, error) {
+	files, err := loadTemplates("markdown", markdownTemplateFiles, templateDir)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New("markdown.md").Funcs(markdownFuncs).Parse(string(files["markdown.md"]))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse template markdown.md")
+	}
+	return t, nil
+}
+
+func renderMarkdown(t *template.Template, name string, page markdownPage) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, name, page); err != nil {
+		return nil, errors.Wrapf(err, "failed to render %s", name)
+	}
+	return buf.Bytes(), nil
+}
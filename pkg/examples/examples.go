@@ -0,0 +1,218 @@
+package examples
+
+import (
+	"math"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// formatExamples are the examples of string formats.
+var formatExamples = map[string]string{
+	"date-time": "2019-01-01T00:00:00Z",
+	"date":      "2019-01-01",
+	"time":      "00:00:00Z",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"uri":       "https://example.com/",
+	"url":       "https://example.com/",
+	"uuid":      "00000000-0000-4000-8000-000000000000",
+	"byte":      "c3RyaW5n",
+	"password":  "password",
+}
+
+// Generate returns a deterministic example value of the schema, respecting
+// example, default, const, enum, format, pattern and range keywords.
+// Component schemas referenced while generating their own example are
+// omitted.
+func Generate(oapi *openapi3.OpenAPIObject, schema openapi3.Schema) interface{} {
+	g := &generator{oapi: oapi, generating: map[string]bool{}}
+	value, _ := g.generate(schema)
+	return value
+}
+
+type generator struct {
+	oapi *openapi3.OpenAPIObject
+	// generating records the component schemas being generated.
+	generating map[string]bool
+}
+
+// generate returns the example of the schema, or false if the schema is a
+// reference to a component schema being generated.
+func (g *generator) generate(schema interface{}) (interface{}, bool) {
+	obj, id := g.oapi.ResolveSchema(schema)
+	if id != "" {
+		if g.generating[id] {
+			return nil, false
+		}
+		g.generating[id] = true
+		defer delete(g.generating, id)
+	}
+	if obj == nil {
+		return nil, true
+	}
+
+	for _, keyword := range []string{"example", "default", "const"} {
+		if value, ok := obj[keyword]; ok {
+			return value, true
+		}
+	}
+	if values, ok := obj["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[0], true
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if subschemas, ok := obj[keyword].([]interface{}); ok && len(subschemas) > 0 {
+			return g.generate(subschemas[0])
+		}
+	}
+
+	switch schemaType(obj) {
+	case "object":
+		return g.object(obj), true
+	case "array":
+		return g.array(obj), true
+	case "string":
+		return generateString(obj), true
+	case "integer":
+		return int(math.Ceil(generateNumber(obj, 1))), true
+	case "number":
+		return generateNumber(obj, 0.5), true
+	case "boolean":
+		return true, true
+	default:
+		return nil, true
+	}
+}
+
+// object returns the example of an object schema, with the properties of
+// allOf subschemas.
+func (g *generator) object(obj map[string]interface{}) map[string]interface{} {
+	value := map[string]interface{}{}
+	if subschemas, ok := obj["allOf"].([]interface{}); ok {
+		for _, subschema := range subschemas {
+			if properties, ok := g.generate(subschema); ok {
+				if properties, ok := properties.(map[string]interface{}); ok {
+					for name, property := range properties {
+						value[name] = property
+					}
+				}
+			}
+		}
+	}
+	properties, _ := obj["properties"].(map[string]interface{})
+	for _, name := range openapi3.SortedKeys(properties) {
+		if property, ok := g.generate(properties[name]); ok {
+			value[name] = property
+		}
+	}
+	return value
+}
+
+// array returns the example of an array schema, with minItems items, or one
+// item.
+func (g *generator) array(obj map[string]interface{}) []interface{} {
+	count := 1
+	if minItems, ok := number(obj["minItems"]); ok && minItems > 1 {
+		count = int(minItems)
+	}
+	if maxItems, ok := number(obj["maxItems"]); ok && int(maxItems) < count {
+		count = int(maxItems)
+	}
+
+	items := []interface{}{}
+	for i := 0; i < count; i++ {
+		item, ok := g.generate(obj["items"])
+		if !ok {
+			break
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// generateString returns an example of the format or pattern of a string
+// schema, or "string" adjusted to its length range.
+func generateString(obj map[string]interface{}) string {
+	if format, ok := obj["format"].(string); ok {
+		if example, ok := formatExamples[format]; ok {
+			return example
+		}
+	}
+	if pattern, ok := obj["pattern"].(string); ok {
+		if str, ok := generatePattern(pattern); ok {
+			return str
+		}
+	}
+
+	str := "string"
+	if minLength, ok := number(obj["minLength"]); ok && int(minLength) > len(str) {
+		str += strings.Repeat("x", int(minLength)-len(str))
+	}
+	if maxLength, ok := number(obj["maxLength"]); ok && int(maxLength) < len(str) {
+		str = str[:int(maxLength)]
+	}
+	return str
+}
+
+// generateNumber returns 0, or the closest value in the range of a numeric
+// schema. Exclusive bounds are moved by the step.
+func generateNumber(obj map[string]interface{}, step float64) float64 {
+	value := 0.0
+	if minimum, ok := number(obj["minimum"]); ok && value <= minimum {
+		value = minimum
+		if obj["exclusiveMinimum"] == true {
+			value += step
+		}
+	} else if minimum, ok := number(obj["exclusiveMinimum"]); ok && value <= minimum {
+		value = minimum + step
+	}
+	if maximum, ok := number(obj["maximum"]); ok && value >= maximum {
+		value = maximum
+		if obj["exclusiveMaximum"] == true {
+			value -= step
+		}
+	} else if maximum, ok := number(obj["exclusiveMaximum"]); ok && value >= maximum {
+		value = maximum - step
+	}
+	if multipleOf, ok := number(obj["multipleOf"]); ok && multipleOf > 0 {
+		value = math.Ceil(value/multipleOf) * multipleOf
+	}
+	return value
+}
+
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// schemaType returns the type of the schema, inferring objects from
+// properties and allOf.
+func schemaType(obj map[string]interface{}) string {
+	switch t := obj["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, value := range t {
+			if str, ok := value.(string); ok && str != "null" {
+				return str
+			}
+		}
+	}
+	if obj["properties"] != nil || obj["allOf"] != nil {
+		return "object"
+	}
+	if obj["items"] != nil {
+		return "array"
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package examples
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/overlay"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/User'}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+              examples:
+                Test: {value: {id: user-1}}
+        "204": {description: No content}
+components:
+  schemas:
+    Base:
+      type: object
+      properties:
+        id: {type: string, example: user-1}
+    User:
+      allOf:
+      - $ref: '#/components/schemas/Base'
+      - type: object
+        properties:
+          name: {type: string, minLength: 8, maxLength: 10}
+          code: {type: string, pattern: '^[A-Z]{3}-\d+(\.\d)?$'}
+          email: {type: string, format: email}
+          age: {type: integer, minimum: 18, exclusiveMinimum: true}
+          score: {type: number, maximum: -1.5}
+          multiple: {type: integer, minimum: 5, multipleOf: 3}
+          active: {type: boolean, default: false}
+          role: {type: string, enum: [admin, member]}
+          tags: {type: array, items: {type: string}, minItems: 2}
+          friends: {type: array, items: {$ref: '#/components/schemas/User'}}
+          pet: {oneOf: [{type: integer}, {type: string}]}
+`
+
+func TestGenerate(t *testing.T) {
+	Convey("Generate", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+
+		value := Generate(oapi, openapi3.MakeSchemaRef("User"))
+		So(value, ShouldResemble, map[string]interface{}{
+			"id":       "user-1",
+			"name":     "stringxx",
+			"code":     "AAA-0.0",
+			"email":    "user@example.com",
+			"age":      19,
+			"score":    -1.5,
+			"multiple": 6,
+			"active":   false,
+			"role":     "admin",
+			"tags":     []interface{}{"string", "string"},
+			"friends":  []interface{}{},
+			"pet":      0,
+		})
+		So(regexp.MustCompile(`^[A-Z]{3}-\d+(\.\d)?$`).MatchString(value.(map[string]interface{})["code"].(string)), ShouldBeTrue)
+	})
+}
+
+func TestSynthesize(t *testing.T) {
+	Convey("Synthesize", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+		Synthesize(oapi)
+
+		op := oapi.Paths["/users"].Post
+		body := op.RequestBody.(*openapi3.RequestBodyObject).Content["application/json"]
+		So(body.Examples, ShouldHaveLength, 1)
+		So(body.Examples[GeneratedExample].Extensions, ShouldResemble, map[string]interface{}{"x-generated": true})
+		So(body.Examples[GeneratedExample].Value, ShouldResemble, Generate(oapi, openapi3.MakeSchemaRef("User")))
+
+		response := op.Responses["200"].(*openapi3.ResponseObject).Content["application/json"]
+		So(response.Examples, ShouldResemble, map[string]openapi3.ExampleObject{
+			"Test": {Value: map[string]interface{}{"id": "user-1"}},
+		})
+
+		Convey("should synthesize webhook examples after overlays", func() {
+			oapi, err := openapi3.Load([]byte(testDocument))
+			So(err, ShouldBeNil)
+			o, err := overlay.Load([]byte(`
+overlay: 1.0.0
+info: {title: Webhooks, version: 1.0.0}
+actions:
+- target: $
+  update:
+    x-webhooks:
+      user.created:
+        post:
+          requestBody:
+            content:
+              application/json:
+                schema: {$ref: '#/components/schemas/Base'}
+          responses:
+            "200": {description: OK}
+`))
+			So(err, ShouldBeNil)
+			oapi, err = o.Apply(oapi)
+			So(err, ShouldBeNil)
+			Synthesize(oapi)
+
+			webhooks, ok := oapi.Extensions["x-webhooks"].(openapi3.WebhooksObject)
+			So(ok, ShouldBeTrue)
+			body := webhooks["user.created"].Post.RequestBody.(*openapi3.RequestBodyObject).Content["application/json"]
+			So(body.Examples[GeneratedExample].Value, ShouldResemble, map[string]interface{}{"id": "user-1"})
+		})
+	})
+}
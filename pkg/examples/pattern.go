@@ -0,0 +1,74 @@
+package examples
+
+import (
+	"regexp/syntax"
+	"strings"
+)
+
+// generatePattern returns a string matching the regular expression: the
+// first alternative, the first character of classes, and the minimum
+// repetitions of quantified expressions, with at least one repetition.
+func generatePattern(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	writePattern(&b, re.Simplify())
+	return b.String(), true
+}
+
+func writePattern(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) > 0 {
+			b.WriteRune(classRune(re.Rune))
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a')
+	case syntax.OpCapture:
+		writePattern(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writePattern(b, sub)
+		}
+	case syntax.OpAlternate:
+		writePattern(b, re.Sub[0])
+	case syntax.OpPlus:
+		writePattern(b, re.Sub[0])
+	case syntax.OpStar, syntax.OpQuest:
+		// Optional expressions are generated once, to be recognizable.
+		writePattern(b, re.Sub[0])
+	case syntax.OpRepeat:
+		count := re.Min
+		if count == 0 && re.Max != 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			writePattern(b, re.Sub[0])
+		}
+	}
+}
+
+// classRune returns the first printable rune of the ranges of a character
+// class, preferring letters and digits.
+func classRune(ranges []rune) rune {
+	for _, preferred := range []rune{'a', 'A', '0'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= preferred && preferred <= ranges[i+1] {
+				return preferred
+			}
+		}
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if ranges[i] >= ' ' {
+			return ranges[i]
+		}
+		if ranges[i+1] >= ' ' {
+			return ' '
+		}
+	}
+	return ranges[0]
+}
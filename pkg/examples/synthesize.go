@@ -0,0 +1,104 @@
+package examples
+
+import (
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"gopkg.in/yaml.v2"
+)
+
+// GeneratedExtension marks examples generated from schemas.
+const GeneratedExtension = "x-generated"
+
+// GeneratedExample is the name of examples generated from schemas.
+const GeneratedExample = "generated"
+
+// Synthesize adds an example generated from the schema to each media type
+// with a schema and without examples, in operations, callbacks, webhooks and
+// components. Generated examples are marked with the x-generated extension.
+func Synthesize(oapi *openapi3.OpenAPIObject) {
+	for _, item := range oapi.Paths {
+		synthesizePathItem(oapi, item)
+	}
+	for _, item := range oapi.Webhooks {
+		synthesizePathItem(oapi, item)
+	}
+	if webhooks, ok := decodeWebhooks(oapi.Extensions["x-webhooks"]); ok {
+		for _, item := range webhooks {
+			synthesizePathItem(oapi, item)
+		}
+		oapi.Extensions["x-webhooks"] = webhooks
+	}
+	for _, item := range oapi.Components.PathItems {
+		synthesizePathItem(oapi, *item)
+	}
+	for _, body := range oapi.Components.RequestBodies {
+		synthesizeContent(oapi, body.Content)
+	}
+	for _, response := range oapi.Components.Responses {
+		synthesizeContent(oapi, response.Content)
+	}
+	for _, callback := range oapi.Components.Callbacks {
+		for _, item := range *callback {
+			synthesizePathItem(oapi, item)
+		}
+	}
+}
+
+// decodeWebhooks decodes the x-webhooks extension of OpenAPI 3.0 documents,
+// which is a generic value in loaded documents, e.g. after merging into a base
+// document or applying overlays.
+func decodeWebhooks(value interface{}) (openapi3.WebhooksObject, bool) {
+	switch value := value.(type) {
+	case nil:
+		return nil, false
+	case openapi3.WebhooksObject:
+		return value, true
+	}
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var webhooks openapi3.WebhooksObject
+	if err := yaml.Unmarshal(data, &webhooks); err != nil {
+		return nil, false
+	}
+	return webhooks, true
+}
+
+func synthesizePathItem(oapi *openapi3.OpenAPIObject, item openapi3.PathItemObject) {
+	for _, method := range openapi3.Methods {
+		op := item.GetOperation(method)
+		if op == nil {
+			continue
+		}
+		if body, ok := op.RequestBody.(*openapi3.RequestBodyObject); ok {
+			synthesizeContent(oapi, body.Content)
+		}
+		for _, response := range op.Responses {
+			if response, ok := response.(*openapi3.ResponseObject); ok {
+				synthesizeContent(oapi, response.Content)
+			}
+		}
+		for _, callback := range op.Callbacks {
+			if callback, ok := callback.(*openapi3.CallbackObject); ok {
+				for _, item := range *callback {
+					synthesizePathItem(oapi, item)
+				}
+			}
+		}
+	}
+}
+
+func synthesizeContent(oapi *openapi3.OpenAPIObject, content map[string]openapi3.MediaTypeObject) {
+	for mediaType, mt := range content {
+		if mt.Schema == nil || len(mt.Examples) > 0 || mt.Example != nil {
+			continue
+		}
+		mt.Examples = map[string]openapi3.ExampleObject{
+			GeneratedExample: {
+				Value:      Generate(oapi, mt.Schema),
+				Extensions: map[string]interface{}{GeneratedExtension: true},
+			},
+		}
+		content[mediaType] = mt
+	}
+}
@@ -0,0 +1,149 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPointer(t *testing.T) {
+	Convey("JSON pointer", t, func() {
+		doc := map[string]interface{}{
+			"paths": map[string]interface{}{
+				"/user/{id}": []interface{}{"a", "b"},
+			},
+			"a~b": 1,
+		}
+
+		So(EscapePointerToken("/user/{id}"), ShouldEqual, "~1user~1{id}")
+		So(UnescapePointerToken("~1user~1{id}"), ShouldEqual, "/user/{id}")
+
+		value, err := ResolvePointer(doc, "/paths/~1user~1{id}/1")
+		So(err, ShouldBeNil)
+		So(value, ShouldEqual, "b")
+
+		value, err = ResolvePointer(doc, "/a~0b")
+		So(err, ShouldBeNil)
+		So(value, ShouldEqual, 1)
+
+		_, err = ResolvePointer(doc, "/paths/~1user~1{id}/2")
+		So(err, ShouldNotBeNil)
+		_, err = ResolvePointer(doc, "paths")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	compile := func(schema string) *Schema {
+		var root interface{}
+		err := json.Unmarshal([]byte(schema), &root)
+		So(err, ShouldBeNil)
+		s, err := NewCompiler(root).Compile("#")
+		So(err, ShouldBeNil)
+		return s
+	}
+	validate := func(s *Schema, instance string) []string {
+		var value interface{}
+		err := json.Unmarshal([]byte(instance), &value)
+		So(err, ShouldBeNil)
+		var errs []string
+		for _, err := range s.Validate(value) {
+			errs = append(errs, err.Error())
+		}
+		return errs
+	}
+
+	Convey("Validate", t, func() {
+		Convey("should validate objects", func() {
+			s := compile(`{
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": { "type": "string", "minLength": 1 },
+					"age": { "type": "integer", "minimum": 0 }
+				},
+				"patternProperties": { "^x-": {} },
+				"additionalProperties": false
+			}`)
+
+			So(validate(s, `{"name": "Test", "age": 1, "x-ext": true}`), ShouldBeEmpty)
+			So(validate(s, `[]`), ShouldResemble, []string{
+				"/: must be object, got array",
+			})
+			So(validate(s, `{"name": "", "age": 1.5, "other": 1}`), ShouldResemble, []string{
+				"/age: must be integer, got number",
+				"/name: must have at least 1 characters",
+				"/: unexpected property \"other\"",
+			})
+			So(validate(s, `{}`), ShouldResemble, []string{
+				"/: missing required property \"name\"",
+			})
+		})
+
+		Convey("should validate arrays", func() {
+			s := compile(`{
+				"type": "array",
+				"items": { "enum": ["a", "b", "c"] },
+				"maxItems": 2,
+				"uniqueItems": true
+			}`)
+
+			So(validate(s, `["a", "b"]`), ShouldBeEmpty)
+			So(validate(s, `["a", "a", "d"]`), ShouldResemble, []string{
+				"/: must have at most 2 items",
+				"/: items 0 and 1 must be unique",
+				"/2: must be one of [\"a\",\"b\",\"c\"]",
+			})
+		})
+
+		Convey("should resolve recursive references", func() {
+			s := compile(`{
+				"definitions": {
+					"Node": {
+						"type": "object",
+						"properties": {
+							"children": { "type": "array", "items": { "$ref": "#/definitions/Node" } }
+						},
+						"additionalProperties": false
+					}
+				},
+				"$ref": "#/definitions/Node"
+			}`)
+
+			So(validate(s, `{"children": [{"children": []}]}`), ShouldBeEmpty)
+			So(validate(s, `{"children": [{"value": 1}]}`), ShouldResemble, []string{
+				"/children/0: unexpected property \"value\"",
+			})
+		})
+
+		Convey("should report closest branch of oneOf", func() {
+			s := compile(`{
+				"oneOf": [
+					{ "type": "object", "required": ["$ref"] },
+					{ "type": "object", "properties": { "type": { "enum": ["string"] } } }
+				]
+			}`)
+
+			So(validate(s, `{"type": "string"}`), ShouldBeEmpty)
+			So(validate(s, `{"type": "bool"}`), ShouldResemble, []string{
+				"/type: must be one of [\"string\"]",
+			})
+		})
+
+		Convey("should support nullable", func() {
+			var root interface{} = map[string]interface{}{"type": "string", "nullable": true}
+			s, err := NewOpenAPICompiler(root).Compile("#")
+			So(err, ShouldBeNil)
+			So(s.Validate(nil), ShouldBeEmpty)
+			So(s.Validate("a"), ShouldBeEmpty)
+			So(s.Validate(1), ShouldHaveLength, 1)
+		})
+
+		Convey("should validate formats", func() {
+			s := compile(`{ "type": "string", "format": "date-time" }`)
+			So(validate(s, `"2019-10-12T07:20:50Z"`), ShouldBeEmpty)
+			So(validate(s, `"yesterday"`), ShouldHaveLength, 1)
+		})
+	})
+}
@@ -0,0 +1,48 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EscapePointerToken escapes a reference token of JSON pointers.
+func EscapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// UnescapePointerToken unescapes a reference token of JSON pointers.
+func UnescapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
+
+// ResolvePointer returns the value at the JSON pointer in the document.
+func ResolvePointer(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer: %q", pointer)
+	}
+
+	value := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = UnescapePointerToken(token)
+		if obj, ok := asObject(value); ok {
+			child, exists := obj[token]
+			if !exists {
+				return nil, fmt.Errorf("JSON pointer not found: %q", pointer)
+			}
+			value = child
+		} else if arr, ok := asArray(value); ok {
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(arr) {
+				return nil, fmt.Errorf("JSON pointer not found: %q", pointer)
+			}
+			value = arr[i]
+		} else {
+			return nil, fmt.Errorf("JSON pointer not found: %q", pointer)
+		}
+	}
+	return value, nil
+}
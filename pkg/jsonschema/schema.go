@@ -0,0 +1,301 @@
+package jsonschema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Compiler compiles JSON schemas in a root document. References in schemas
+// are resolved as JSON pointers in the root document.
+type Compiler struct {
+	root interface{}
+	// nullable enables the OpenAPI 3.0 nullable keyword.
+	nullable bool
+
+	schemas map[string]*Schema
+}
+
+func NewCompiler(root interface{}) *Compiler {
+	return &Compiler{
+		root:    root,
+		schemas: map[string]*Schema{},
+	}
+}
+
+// NewOpenAPICompiler returns a compiler of schemas in an OpenAPI document,
+// supporting the OpenAPI 3.0 nullable keyword.
+func NewOpenAPICompiler(root interface{}) *Compiler {
+	c := NewCompiler(root)
+	c.nullable = true
+	return c
+}
+
+// Compile compiles the schema at the reference in the root document, e.g.
+// "#/definitions/User".
+func (c *Compiler) Compile(ref string) (*Schema, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported reference: %q", ref)
+	}
+	pointer := ref[1:]
+	if schema, ok := c.schemas[pointer]; ok {
+		return schema, nil
+	}
+
+	value, err := ResolvePointer(c.root, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register before compiling to support recursive schemas.
+	schema := &Schema{}
+	c.schemas[pointer] = schema
+	err = c.compileInto(schema, value)
+	if err != nil {
+		delete(c.schemas, pointer)
+		return nil, fmt.Errorf("%s: %v", ref, err)
+	}
+	return schema, nil
+}
+
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *Schema
+}
+
+// Schema is a compiled JSON schema. Draft 4 keywords are supported, with
+// const and numeric exclusiveMinimum/exclusiveMaximum from later drafts.
+type Schema struct {
+	// alwaysValid/neverValid are boolean schemas.
+	alwaysValid bool
+	neverValid  bool
+
+	ref *Schema
+
+	types    []string
+	nullable bool
+	enum     []interface{}
+	constant *interface{}
+	format   string
+
+	properties           map[string]*Schema
+	patternProperties    []patternSchema
+	additionalProperties *Schema
+	required             []string
+	minProperties        *int
+	maxProperties        *int
+	dependencies         map[string][]string
+
+	items       *Schema
+	itemsList   []*Schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum bool
+	exclusiveMaximum bool
+	multipleOf       *float64
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+func (c *Compiler) compileInto(schema *Schema, value interface{}) (err error) {
+	if b, ok := value.(bool); ok {
+		schema.alwaysValid = b
+		schema.neverValid = !b
+		return nil
+	}
+
+	obj, ok := asObject(value)
+	if !ok {
+		return fmt.Errorf("schema must be an object")
+	}
+
+	if ref, ok := obj["$ref"].(string); ok {
+		// Other keywords are ignored in presence of $ref.
+		schema.ref, err = c.Compile(ref)
+		return
+	}
+
+	switch t := obj["type"].(type) {
+	case string:
+		schema.types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if str, ok := item.(string); ok {
+				schema.types = append(schema.types, str)
+			}
+		}
+	}
+	if c.nullable {
+		schema.nullable, _ = obj["nullable"].(bool)
+	}
+	if enum, ok := asArray(obj["enum"]); ok {
+		schema.enum = enum
+	}
+	if constant, ok := obj["const"]; ok {
+		schema.constant = &constant
+	}
+	schema.format, _ = obj["format"].(string)
+
+	if properties, ok := asObject(obj["properties"]); ok {
+		schema.properties = map[string]*Schema{}
+		for name, propValue := range properties {
+			if schema.properties[name], err = c.compileChild(propValue, "properties", name); err != nil {
+				return
+			}
+		}
+	}
+	if patternProperties, ok := asObject(obj["patternProperties"]); ok {
+		var patterns []string
+		for pattern := range patternProperties {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+			}
+			propSchema, err := c.compileChild(patternProperties[pattern], "patternProperties", pattern)
+			if err != nil {
+				return err
+			}
+			schema.patternProperties = append(schema.patternProperties, patternSchema{re, propSchema})
+		}
+	}
+	if additional, ok := obj["additionalProperties"]; ok {
+		if schema.additionalProperties, err = c.compileChild(additional, "additionalProperties"); err != nil {
+			return
+		}
+	}
+	if required, ok := asArray(obj["required"]); ok {
+		for _, name := range required {
+			if str, ok := name.(string); ok {
+				schema.required = append(schema.required, str)
+			}
+		}
+	}
+	schema.minProperties = intKeyword(obj, "minProperties")
+	schema.maxProperties = intKeyword(obj, "maxProperties")
+	if dependencies, ok := asObject(obj["dependencies"]); ok {
+		schema.dependencies = map[string][]string{}
+		for name, dep := range dependencies {
+			if names, ok := asArray(dep); ok {
+				for _, depName := range names {
+					if str, ok := depName.(string); ok {
+						schema.dependencies[name] = append(schema.dependencies[name], str)
+					}
+				}
+			}
+		}
+	}
+
+	if items, ok := obj["items"]; ok {
+		if list, ok := asArray(items); ok {
+			for i, item := range list {
+				itemSchema, err := c.compileChild(item, "items", fmt.Sprint(i))
+				if err != nil {
+					return err
+				}
+				schema.itemsList = append(schema.itemsList, itemSchema)
+			}
+		} else if schema.items, err = c.compileChild(items, "items"); err != nil {
+			return
+		}
+	}
+	schema.minItems = intKeyword(obj, "minItems")
+	schema.maxItems = intKeyword(obj, "maxItems")
+	schema.uniqueItems, _ = obj["uniqueItems"].(bool)
+
+	schema.minLength = intKeyword(obj, "minLength")
+	schema.maxLength = intKeyword(obj, "maxLength")
+	if pattern, ok := obj["pattern"].(string); ok {
+		if schema.pattern, err = regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+
+	schema.minimum = numberKeyword(obj, "minimum")
+	schema.maximum = numberKeyword(obj, "maximum")
+	schema.multipleOf = numberKeyword(obj, "multipleOf")
+	switch exclusive := obj["exclusiveMinimum"].(type) {
+	case bool:
+		schema.exclusiveMinimum = exclusive
+	default:
+		if n := numberKeyword(obj, "exclusiveMinimum"); n != nil {
+			schema.minimum = n
+			schema.exclusiveMinimum = true
+		}
+	}
+	switch exclusive := obj["exclusiveMaximum"].(type) {
+	case bool:
+		schema.exclusiveMaximum = exclusive
+	default:
+		if n := numberKeyword(obj, "exclusiveMaximum"); n != nil {
+			schema.maximum = n
+			schema.exclusiveMaximum = true
+		}
+	}
+
+	for _, keyword := range []struct {
+		name   string
+		target *[]*Schema
+	}{
+		{"allOf", &schema.allOf},
+		{"anyOf", &schema.anyOf},
+		{"oneOf", &schema.oneOf},
+	} {
+		list, _ := asArray(obj[keyword.name])
+		for i, item := range list {
+			subSchema, err := c.compileChild(item, keyword.name, fmt.Sprint(i))
+			if err != nil {
+				return err
+			}
+			*keyword.target = append(*keyword.target, subSchema)
+		}
+	}
+	if not, ok := obj["not"]; ok {
+		if schema.not, err = c.compileChild(not, "not"); err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) compileChild(value interface{}, path ...string) (*Schema, error) {
+	schema := &Schema{}
+	err := c.compileInto(schema, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", strings.Join(path, "/"), err)
+	}
+	return schema, nil
+}
+
+func intKeyword(obj map[string]interface{}, keyword string) *int {
+	n, ok := asNumber(obj[keyword])
+	if !ok {
+		return nil
+	}
+	i := int(n)
+	return &i
+}
+
+func numberKeyword(obj map[string]interface{}, keyword string) *float64 {
+	n, ok := asNumber(obj[keyword])
+	if !ok {
+		return nil
+	}
+	return &n
+}
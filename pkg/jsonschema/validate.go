@@ -0,0 +1,346 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError describes a value in the instance violating the schema.
+type ValidationError struct {
+	// Pointer is the JSON pointer of the invalid value in the instance.
+	Pointer string
+	Message string
+}
+
+func (err ValidationError) Error() string {
+	pointer := err.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s", pointer, err.Message)
+}
+
+// Validate validates the instance against the schema, returning all
+// violations found.
+func (s *Schema) Validate(instance interface{}) []ValidationError {
+	return s.validate(instance, "")
+}
+
+func (s *Schema) validate(instance interface{}, pointer string) (errs []ValidationError) {
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case s.alwaysValid:
+		return nil
+	case s.neverValid:
+		fail("no value is allowed")
+		return
+	case s.ref != nil:
+		return s.ref.validate(instance, pointer)
+	}
+
+	if instance == nil && s.nullable {
+		return nil
+	}
+
+	instanceType := TypeOf(instance)
+	if len(s.types) > 0 && !matchType(s.types, instanceType) {
+		fail("must be %s, got %s", strings.Join(s.types, " or "), instanceType)
+		return
+	}
+
+	if s.enum != nil {
+		found := false
+		for _, value := range s.enum {
+			if Equal(instance, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %s", jsonString(s.enum))
+		}
+	}
+	if s.constant != nil && !Equal(instance, *s.constant) {
+		fail("must be %s", jsonString(*s.constant))
+	}
+
+	switch instanceType {
+	case "object":
+		obj, _ := asObject(instance)
+		errs = append(errs, s.validateObject(obj, pointer)...)
+	case "array":
+		arr, _ := asArray(instance)
+		errs = append(errs, s.validateArray(arr, pointer)...)
+	case "string":
+		errs = append(errs, s.validateString(instance.(string), pointer)...)
+	case "integer", "number":
+		n, _ := asNumber(instance)
+		errs = append(errs, s.validateNumber(n, pointer)...)
+	}
+
+	for _, sub := range s.allOf {
+		errs = append(errs, sub.validate(instance, pointer)...)
+	}
+
+	if len(s.anyOf) > 0 {
+		var branchErrs [][]ValidationError
+		matched := false
+		for _, sub := range s.anyOf {
+			subErrs := sub.validate(instance, pointer)
+			if len(subErrs) == 0 {
+				matched = true
+				break
+			}
+			branchErrs = append(branchErrs, subErrs)
+		}
+		if !matched {
+			errs = append(errs, closestBranch(branchErrs)...)
+		}
+	}
+
+	if len(s.oneOf) > 0 {
+		var branchErrs [][]ValidationError
+		matches := 0
+		for _, sub := range s.oneOf {
+			subErrs := sub.validate(instance, pointer)
+			if len(subErrs) == 0 {
+				matches++
+			} else {
+				branchErrs = append(branchErrs, subErrs)
+			}
+		}
+		switch {
+		case matches == 0:
+			errs = append(errs, closestBranch(branchErrs)...)
+		case matches > 1:
+			fail("must match exactly one schema, matched %d", matches)
+		}
+	}
+
+	if s.not != nil && len(s.not.validate(instance, pointer)) == 0 {
+		fail("must not match schema")
+	}
+
+	return
+}
+
+// closestBranch selects the errors of the branch that was closest to be
+// valid: the branch failing deepest in the instance, or with fewest errors.
+func closestBranch(branchErrs [][]ValidationError) []ValidationError {
+	var best []ValidationError
+	bestDepth := -1
+	for _, errs := range branchErrs {
+		depth := 0
+		for _, err := range errs {
+			if d := strings.Count(err.Pointer, "/"); d > depth {
+				depth = d
+			}
+		}
+		if depth > bestDepth || (depth == bestDepth && len(errs) < len(best)) {
+			best = errs
+			bestDepth = depth
+		}
+	}
+	return best
+}
+
+func matchType(types []string, instanceType string) bool {
+	for _, t := range types {
+		if t == instanceType || (t == "number" && instanceType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, pointer string) (errs []ValidationError) {
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range s.required {
+		if _, exists := obj[name]; !exists {
+			fail("missing required property %q", name)
+		}
+	}
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		fail("must have at least %d properties", *s.minProperties)
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		fail("must have at most %d properties", *s.maxProperties)
+	}
+	for name, deps := range s.dependencies {
+		if _, exists := obj[name]; !exists {
+			continue
+		}
+		for _, dep := range deps {
+			if _, exists := obj[dep]; !exists {
+				fail("property %q requires property %q", name, dep)
+			}
+		}
+	}
+
+	for _, name := range sortedNames(obj) {
+		value := obj[name]
+		childPointer := pointer + "/" + EscapePointerToken(name)
+		matched := false
+		if propSchema, ok := s.properties[name]; ok {
+			matched = true
+			errs = append(errs, propSchema.validate(value, childPointer)...)
+		}
+		for _, pattern := range s.patternProperties {
+			if pattern.pattern.MatchString(name) {
+				matched = true
+				errs = append(errs, pattern.schema.validate(value, childPointer)...)
+			}
+		}
+		if !matched && s.additionalProperties != nil {
+			if s.additionalProperties.neverValid {
+				fail("unexpected property %q", name)
+			} else {
+				errs = append(errs, s.additionalProperties.validate(value, childPointer)...)
+			}
+		}
+	}
+	return
+}
+
+func (s *Schema) validateArray(arr []interface{}, pointer string) (errs []ValidationError) {
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.minItems != nil && len(arr) < *s.minItems {
+		fail("must have at least %d items", *s.minItems)
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		fail("must have at most %d items", *s.maxItems)
+	}
+	if s.uniqueItems {
+		for i := range arr {
+			for j := 0; j < i; j++ {
+				if Equal(arr[i], arr[j]) {
+					fail("items %d and %d must be unique", j, i)
+				}
+			}
+		}
+	}
+
+	for i, item := range arr {
+		itemPointer := pointer + "/" + strconv.Itoa(i)
+		if s.items != nil {
+			errs = append(errs, s.items.validate(item, itemPointer)...)
+		} else if i < len(s.itemsList) {
+			errs = append(errs, s.itemsList[i].validate(item, itemPointer)...)
+		}
+	}
+	return
+}
+
+func (s *Schema) validateString(str string, pointer string) (errs []ValidationError) {
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(str)
+	if s.minLength != nil && length < *s.minLength {
+		fail("must have at least %d characters", *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		fail("must have at most %d characters", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		fail("must match pattern %q", s.pattern.String())
+	}
+	if s.format != "" && !validFormat(s.format, str) {
+		fail("must be a valid %s", s.format)
+	}
+	return
+}
+
+func (s *Schema) validateNumber(n float64, pointer string) (errs []ValidationError) {
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.minimum != nil {
+		if s.exclusiveMinimum && n <= *s.minimum {
+			fail("must be greater than %v", *s.minimum)
+		} else if n < *s.minimum {
+			fail("must be greater than or equal to %v", *s.minimum)
+		}
+	}
+	if s.maximum != nil {
+		if s.exclusiveMaximum && n >= *s.maximum {
+			fail("must be less than %v", *s.maximum)
+		} else if n > *s.maximum {
+			fail("must be less than or equal to %v", *s.maximum)
+		}
+	}
+	if s.multipleOf != nil && *s.multipleOf > 0 {
+		quotient := n / *s.multipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			fail("must be a multiple of %v", *s.multipleOf)
+		}
+	}
+	return
+}
+
+var uuidFormat = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validFormat checks common string formats; unknown formats are valid.
+func validFormat(format string, str string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, str)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", str)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(str)
+		return err == nil
+	case "uri":
+		u, err := url.Parse(str)
+		return err == nil && u.IsAbs()
+	case "uri-reference":
+		_, err := url.Parse(str)
+		return err == nil
+	case "uuid":
+		return uuidFormat.MatchString(str)
+	case "regex":
+		_, err := regexp.Compile(str)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+func sortedNames(obj map[string]interface{}) []string {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func jsonString(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
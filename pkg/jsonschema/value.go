@@ -0,0 +1,128 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+)
+
+// asObject returns the JSON object of a map with string keys.
+func asObject(value interface{}) (map[string]interface{}, bool) {
+	if obj, ok := value.(map[string]interface{}); ok {
+		return obj, true
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	obj := make(map[string]interface{}, v.Len())
+	for _, key := range v.MapKeys() {
+		obj[key.String()] = v.MapIndex(key).Interface()
+	}
+	return obj, true
+}
+
+// asArray returns the JSON array of a slice.
+func asArray(value interface{}) ([]interface{}, bool) {
+	if arr, ok := value.([]interface{}); ok {
+		return arr, true
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+	arr := make([]interface{}, v.Len())
+	for i := range arr {
+		arr[i] = v.Index(i).Interface()
+	}
+	return arr, true
+}
+
+// asNumber returns the value of a JSON number.
+func asNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// TypeOf returns the JSON Schema type of a value.
+func TypeOf(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	if n, ok := asNumber(value); ok {
+		if n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	if _, ok := asArray(value); ok {
+		return "array"
+	}
+	if _, ok := asObject(value); ok {
+		return "object"
+	}
+	return "unknown"
+}
+
+// Equal reports whether two JSON values are equal.
+func Equal(a interface{}, b interface{}) bool {
+	if na, ok := asNumber(a); ok {
+		nb, ok := asNumber(b)
+		return ok && na == nb
+	}
+	if arrA, ok := asArray(a); ok {
+		arrB, ok := asArray(b)
+		if !ok || len(arrA) != len(arrB) {
+			return false
+		}
+		for i := range arrA {
+			if !Equal(arrA[i], arrB[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if objA, ok := asObject(a); ok {
+		objB, ok := asObject(b)
+		if !ok || len(objA) != len(objB) {
+			return false
+		}
+		for key, value := range objA {
+			other, exists := objB[key]
+			if !exists || !Equal(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
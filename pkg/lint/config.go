@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityWarning Severity = "warn"
+	SeverityError   Severity = "error"
+)
+
+func (s Severity) Validate() bool {
+	switch s {
+	case SeverityOff, SeverityWarning, SeverityError:
+		return true
+	default:
+		return false
+	}
+}
+
+// Config configures the severities of rules. Rules not configured are
+// reported with their default severities.
+type Config struct {
+	Rules map[string]Severity `yaml:"rules"`
+}
+
+// ParseConfig parses a YAML lint configuration, e.g.:
+//
+//	rules:
+//	  operation-tags: warn
+//	  path-plural-collections: off
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, err
+	}
+	for name, severity := range config.Rules {
+		if _, ok := findRule(name); !ok {
+			return nil, fmt.Errorf("unknown lint rule: %v", name)
+		}
+		if !severity.Validate() {
+			return nil, fmt.Errorf("invalid severity of lint rule %v: %v", name, severity)
+		}
+	}
+	return config, nil
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+func (c *Config) severity(rule Rule) Severity {
+	if c != nil {
+		if severity, ok := c.Rules[rule.Name]; ok {
+			return severity
+		}
+	}
+	return rule.Severity
+}
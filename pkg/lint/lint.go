@@ -0,0 +1,91 @@
+package lint
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+)
+
+// Diagnostic is a violation of a lint rule.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	// Pointer is the JSON pointer of the offending value in the document.
+	Pointer string
+	// Position is the source position of the annotation producing the
+	// offending value, if known.
+	Position token.Position
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	location := d.Pointer
+	if d.Position.IsValid() {
+		location = d.Position.String()
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", location, d.Severity, d.Message, d.Rule)
+}
+
+// Lint checks the document against the lint rules. Diagnostics suppressed by
+// ignore directives in the source map are omitted.
+func Lint(oapi *openapi3.OpenAPIObject, config *Config, sourceMap processor.SourceMap) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, rule := range Rules {
+		severity := config.severity(rule)
+		if severity == SeverityOff {
+			continue
+		}
+
+		rule.check(oapi, func(pointer string, format string, args ...interface{}) {
+			source, _ := sourceMap.Lookup(pointer)
+			if source.Ignores(rule.Name) || ignoredByOperations(sourceMap, pointer, rule.Name) {
+				return
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Rule:     rule.Name,
+				Severity: severity,
+				Pointer:  pointer,
+				Position: source.Position,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		})
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Position, diagnostics[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+	return diagnostics
+}
+
+// ignoredByOperations returns whether the rule is suppressed at the path item
+// of the JSON pointer by an operation of the path. Operations of a path item
+// are declared separately, and the path item is located at the first one.
+func ignoredByOperations(sourceMap processor.SourceMap, pointer string, rule string) bool {
+	if !strings.HasPrefix(pointer, "/paths/") || strings.Count(pointer, "/") != 2 {
+		return false
+	}
+	for _, method := range openapi3.Methods {
+		if source, ok := sourceMap[pointer+openapi3.Pointer(strings.ToLower(method))]; ok && source.Ignores(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasErrors returns whether any diagnostic has error severity.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package lint
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/processor"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLint(t *testing.T) {
+	lint := func(config *Config, src string) []string {
+		psr := processor.New()
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "api.go", src, parser.ParseComments)
+		So(err, ShouldBeNil)
+		psr.Process(fset, file)
+		oapi, errs := psr.End()
+		So(errs, ShouldBeEmpty)
+
+		var diagnostics []string
+		for _, d := range Lint(oapi, config, psr.SourceMap()) {
+			diagnostics = append(diagnostics, d.String())
+		}
+		return diagnostics
+	}
+
+	Convey("Lint", t, func() {
+		Convey("should accept conforming operations", func() {
+			So(lint(nil, `package main
+
+/*
+	@Operation GET /user-groups/{id}/members - List members of group
+		@Tag Group
+		@Response 200
+			OK.
+		@Response 404
+			Group not found.
+*/
+func listMembers() {}
+
+/*
+	@Operation POST /people - Create person
+		@Tag Person
+		@RequestBody
+			@JSONSchema {Person}
+		@Response 400
+			Invalid request.
+*/
+func createPerson() {}
+`), ShouldBeEmpty)
+		})
+
+		Convey("should report violations", func() {
+			So(lint(nil, `package main
+
+/*
+	@Operation POST /userGroup/{id} - Update a user group with a summary that is much too long to be read at a glance in the list
+		@RequestBody
+			@JSONSchema
+				{ "type": "object" }
+		@Response 200
+			OK.
+*/
+func updateGroup() {}
+`), ShouldResemble, []string{
+				`api.go:4:2: error: path segment "userGroup" is not kebab-case [path-kebab-case]`,
+				`api.go:4:2: error: collection path segment "userGroup" is not plural [path-plural-collections]`,
+				`api.go:4:2: error: operation has no tags [operation-tags]`,
+				`api.go:4:2: error: operation has no 4xx response [operation-4xx-response]`,
+				`api.go:4:2: error: summary has 91 characters, exceeding 80 [operation-summary-length]`,
+				`api.go:5:3: error: request body schema of application/json is inline [no-inline-request-schema]`,
+			})
+		})
+
+		Convey("should apply configured severities and suppressions", func() {
+			config, err := ParseConfig([]byte(`
+rules:
+  operation-tags: warn
+  operation-4xx-response: off
+`))
+			So(err, ShouldBeNil)
+
+			So(lint(config, `package main
+
+/*
+	@Operation GET /user/{id} - Get user
+		@Response 200
+			OK.
+*/
+func getUser() {}
+
+//openapi3-gen:ignore path-plural-collections operation-tags
+/*
+	@Operation DELETE /user/{id} - Delete user
+		@Response 204
+			Deleted.
+*/
+func deleteUser() {}
+
+/*
+	@Operation GET /health-check - Check health
+		@Response 200
+			OK.
+*/
+func checkHealth() {}
+`), ShouldResemble, []string{
+				`api.go:4:2: warn: operation has no tags [operation-tags]`,
+				`api.go:19:2: error: collection path segment "health-check" is not plural [path-plural-collections]`,
+				`api.go:19:2: warn: operation has no tags [operation-tags]`,
+			})
+		})
+
+		Convey("should apply suppressions of any operation to path rules", func() {
+			So(lint(nil, `package main
+
+/*
+	@Operation GET /user_groups - Get user group
+		@Tag Group
+		@Response 404
+			Not found.
+*/
+func getGroup() {}
+
+//openapi3-gen:ignore path-kebab-case operation-tags
+/*
+	@Operation POST /user_groups - Update user group
+		@Response 404
+			Not found.
+*/
+func updateGroup() {}
+
+/*
+	@Operation PUT /user_groups - Replace user group
+		@Response 404
+			Not found.
+*/
+func replaceGroup() {}
+`), ShouldResemble, []string{
+				`api.go:20:2: error: operation has no tags [operation-tags]`,
+			})
+		})
+
+		Convey("should reject invalid configurations", func() {
+			_, err := ParseConfig([]byte("rules:\n  unknown: error\n"))
+			So(err, ShouldBeError, "unknown lint rule: unknown")
+			_, err = ParseConfig([]byte("rules:\n  operation-tags: fatal\n"))
+			So(err, ShouldBeError, "invalid severity of lint rule operation-tags: fatal")
+		})
+	})
+}
@@ -0,0 +1,211 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// MaxSummaryLength is the maximum length of operation summaries.
+const MaxSummaryLength = 80
+
+// reporter reports a violation of the rule at the JSON pointer.
+type reporter func(pointer string, format string, args ...interface{})
+
+type Rule struct {
+	Name        string
+	Description string
+	// Severity is the default severity of the rule.
+	Severity Severity
+	check    func(oapi *openapi3.OpenAPIObject, report reporter)
+}
+
+// Rules are the built-in lint rules.
+var Rules = []Rule{
+	{
+		Name:        "path-kebab-case",
+		Description: "path segments must be kebab-case",
+		Severity:    SeverityError,
+		check:       checkPathKebabCase,
+	},
+	{
+		Name:        "path-plural-collections",
+		Description: "collection path segments must be plural",
+		Severity:    SeverityError,
+		check:       checkPathPluralCollections,
+	},
+	{
+		Name:        "operation-tags",
+		Description: "operations must be tagged",
+		Severity:    SeverityError,
+		check:       checkOperationTags,
+	},
+	{
+		Name:        "operation-4xx-response",
+		Description: "operations must have a 4xx response",
+		Severity:    SeverityError,
+		check:       checkOperation4xxResponse,
+	},
+	{
+		Name:        "operation-summary-length",
+		Description: fmt.Sprintf("operation summaries must be at most %d characters", MaxSummaryLength),
+		Severity:    SeverityError,
+		check:       checkOperationSummaryLength,
+	},
+	{
+		Name:        "no-inline-request-schema",
+		Description: "request body schemas must reference component schemas",
+		Severity:    SeverityError,
+		check:       checkNoInlineRequestSchema,
+	},
+}
+
+func findRule(name string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+var kebabCaseSegment = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// irregularPlurals are plural nouns not ending with "s".
+var irregularPlurals = map[string]bool{
+	"children": true,
+	"data":     true,
+	"feet":     true,
+	"media":    true,
+	"men":      true,
+	"people":   true,
+	"women":    true,
+}
+
+func isParameterSegment(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func isPlural(segment string) bool {
+	words := strings.Split(segment, "-")
+	word := strings.ToLower(words[len(words)-1])
+	if irregularPlurals[word] {
+		return true
+	}
+	return strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss")
+}
+
+// operationPointer returns the JSON pointer of the operation.
+func operationPointer(path string, method string) string {
+	return openapi3.Pointer("paths", path, strings.ToLower(method))
+}
+
+// forEachOperation calls fn with operations in paths, sorted by path and
+// method.
+func forEachOperation(oapi *openapi3.OpenAPIObject, fn func(path string, method string, op *openapi3.OperationObject)) {
+	for _, path := range openapi3.SortedKeys(oapi.Paths) {
+		pathItem := oapi.Paths[path]
+		for _, method := range openapi3.Methods {
+			if op := pathItem.GetOperation(method); op != nil {
+				fn(path, method, op)
+			}
+		}
+	}
+}
+
+// forEachPath calls fn with path items in paths, sorted by path.
+func forEachPath(oapi *openapi3.OpenAPIObject, fn func(path string, pathItem openapi3.PathItemObject)) {
+	for _, path := range openapi3.SortedKeys(oapi.Paths) {
+		fn(path, oapi.Paths[path])
+	}
+}
+
+func checkPathKebabCase(oapi *openapi3.OpenAPIObject, report reporter) {
+	forEachPath(oapi, func(path string, pathItem openapi3.PathItemObject) {
+		for _, segment := range strings.Split(path, "/") {
+			if segment == "" || isParameterSegment(segment) {
+				continue
+			}
+			if !kebabCaseSegment.MatchString(segment) {
+				report(openapi3.Pointer("paths", path), "path segment %q is not kebab-case", segment)
+			}
+		}
+	})
+}
+
+// checkPathPluralCollections checks segments followed by a parameter, and
+// the last segment of paths listing or creating members (GET or POST).
+func checkPathPluralCollections(oapi *openapi3.OpenAPIObject, report reporter) {
+	forEachPath(oapi, func(path string, pathItem openapi3.PathItemObject) {
+		segments := strings.Split(path, "/")
+		for i, segment := range segments {
+			if segment == "" || isParameterSegment(segment) {
+				continue
+			}
+			if i+1 < len(segments) {
+				if !isParameterSegment(segments[i+1]) {
+					continue
+				}
+			} else if pathItem.Get == nil && pathItem.Post == nil {
+				continue
+			}
+			if !isPlural(segment) {
+				report(openapi3.Pointer("paths", path), "collection path segment %q is not plural", segment)
+			}
+		}
+	})
+}
+
+func checkOperationTags(oapi *openapi3.OpenAPIObject, report reporter) {
+	forEachOperation(oapi, func(path string, method string, op *openapi3.OperationObject) {
+		if len(op.Tags) == 0 {
+			report(operationPointer(path, method), "operation has no tags")
+		}
+	})
+}
+
+func checkOperation4xxResponse(oapi *openapi3.OpenAPIObject, report reporter) {
+	forEachOperation(oapi, func(path string, method string, op *openapi3.OperationObject) {
+		for statusCode := range op.Responses {
+			if strings.HasPrefix(statusCode, "4") {
+				return
+			}
+		}
+		report(operationPointer(path, method)+"/responses", "operation has no 4xx response")
+	})
+}
+
+func checkOperationSummaryLength(oapi *openapi3.OpenAPIObject, report reporter) {
+	forEachOperation(oapi, func(path string, method string, op *openapi3.OperationObject) {
+		if n := utf8.RuneCountInString(op.Summary); n > MaxSummaryLength {
+			report(operationPointer(path, method)+"/summary", "summary has %d characters, exceeding %d", n, MaxSummaryLength)
+		}
+	})
+}
+
+func checkNoInlineRequestSchema(oapi *openapi3.OpenAPIObject, report reporter) {
+	check := func(pointer string, requestBody *openapi3.RequestBodyObject) {
+		for _, mediaType := range openapi3.SortedKeys(requestBody.Content) {
+			schema := requestBody.Content[mediaType].Schema
+			if schema == nil {
+				continue
+			}
+			if _, isRef := openapi3.RefID(schema, "#/components/schemas/"); !isRef {
+				report(pointer+openapi3.Pointer("content", mediaType, "schema"), "request body schema of %s is inline", mediaType)
+			}
+		}
+	}
+
+	forEachOperation(oapi, func(path string, method string, op *openapi3.OperationObject) {
+		if requestBody, ok := op.RequestBody.(*openapi3.RequestBodyObject); ok {
+			check(operationPointer(path, method)+"/requestBody", requestBody)
+		}
+	})
+
+	for _, id := range openapi3.SortedKeys(oapi.Components.RequestBodies) {
+		check(openapi3.Pointer("components", "requestBodies", id), oapi.Components.RequestBodies[id])
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/router"
+)
+
+type contextKey struct{}
+
+type match struct {
+	route  *router.Route
+	params map[string]string
+}
+
+// Validator is a middleware validating requests against the operations of an
+// OpenAPI document. Schemas of all operations are compiled when created.
+type Validator struct {
+	router *router.Router
+	// basePath is the path of the first server URL, stripped from request
+	// paths.
+	basePath string
+}
+
+// New returns a validator of the document, e.g. loaded with openapi3.Load
+// from an embedded specification.
+func New(oapi *openapi3.OpenAPIObject) (*Validator, error) {
+	r, err := router.New(oapi)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{router: r, basePath: router.BasePath(oapi)}, nil
+}
+
+// Handler returns a handler validating the path, query, header and cookie
+// parameters and JSON body of requests before calling the next handler.
+// Requests not matching an operation, or invalid requests, are rejected with
+// problem details responses (RFC 7807).
+func (v *Validator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, params, err := v.router.Match(r.Method, router.StripBasePath(r.URL.Path, v.basePath))
+		switch err {
+		case nil:
+			break
+		case router.ErrNotFound:
+			router.WriteProblem(w, http.StatusNotFound, "no operation matches the request path", nil)
+			return
+		case router.ErrMethodNotAllowed:
+			router.WriteProblem(w, http.StatusMethodNotAllowed, "no operation matches the request method", nil)
+			return
+		}
+
+		violations, err := route.ValidateRequest(r, params)
+		if err != nil {
+			router.WriteProblem(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		if len(violations) > 0 {
+			router.WriteProblem(w, http.StatusBadRequest, "request is invalid", violations)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), contextKey{}, match{route: route, params: params})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Route returns the route of the request validated by the middleware, and
+// the values of its path parameters.
+func Route(ctx context.Context) (*router.Route, map[string]string) {
+	m, ok := ctx.Value(contextKey{}).(match)
+	if !ok {
+		return nil, nil
+	}
+	return m.route, m.params
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/router"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+servers:
+- url: https://api.example.com/v1
+paths:
+  /users/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: integer}}
+    put:
+      operationId: updateUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string, maxLength: 10}
+      responses:
+        "204": {description: Updated}
+`
+
+func TestValidator(t *testing.T) {
+	Convey("Validator", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+		v, err := New(oapi)
+		So(err, ShouldBeNil)
+
+		var operationID string
+		var params map[string]string
+		handler := v.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var route *router.Route
+			route, params = Route(r.Context())
+			operationID = route.Operation.ID
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			return w
+		}
+		problem := func(w *httptest.ResponseRecorder) router.Problem {
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/problem+json")
+			var p router.Problem
+			So(json.Unmarshal(w.Body.Bytes(), &p), ShouldBeNil)
+			return p
+		}
+
+		Convey("should pass valid requests", func() {
+			w := serve("PUT", "/v1/users/1", `{"name": "Test"}`)
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(operationID, ShouldEqual, "updateUser")
+			So(params, ShouldResemble, map[string]string{"id": "1"})
+		})
+
+		Convey("should reject invalid requests", func() {
+			w := serve("PUT", "/users/a", `{"name": "Long user name"}`)
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(problem(w), ShouldResemble, router.Problem{
+				Type:   "about:blank",
+				Title:  "Bad Request",
+				Status: http.StatusBadRequest,
+				Detail: "request is invalid",
+				Errors: []string{
+					"path id: must be integer, got string",
+					"body/name: must have at most 10 characters",
+				},
+			})
+			So(operationID, ShouldEqual, "")
+		})
+
+		Convey("should reject unknown operations", func() {
+			So(problem(serve("GET", "/users", "")).Status, ShouldEqual, http.StatusNotFound)
+			So(problem(serve("GET", "/users/1", "")).Status, ShouldEqual, http.StatusMethodNotAllowed)
+		})
+	})
+}
@@ -0,0 +1,197 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/examples"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"github.com/skygeario/openapi3-gen/pkg/router"
+)
+
+// Server is an HTTP handler responding to requests of the operations of an
+// OpenAPI document with their examples.
+//
+// The response and example are selected with the Prefer header, e.g.
+// "Prefer: code=404, example=NotFound". By default, the first successful
+// response and its first example in alphabetical order are selected.
+// Responses without examples are generated from their schema.
+type Server struct {
+	oapi   *openapi3.OpenAPIObject
+	router *router.Router
+	// basePath is the path of the first server URL, stripped from request
+	// paths.
+	basePath string
+}
+
+// New returns a mock server of the document.
+func New(oapi *openapi3.OpenAPIObject) (*Server, error) {
+	r, err := router.New(oapi)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{oapi: oapi, router: r, basePath: router.BasePath(oapi)}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, params, err := s.router.Match(r.Method, router.StripBasePath(r.URL.Path, s.basePath))
+	switch err {
+	case nil:
+		break
+	case router.ErrNotFound:
+		router.WriteProblem(w, http.StatusNotFound, "no operation matches the request path", nil)
+		return
+	case router.ErrMethodNotAllowed:
+		router.WriteProblem(w, http.StatusMethodNotAllowed, "no operation matches the request method", nil)
+		return
+	}
+
+	violations, err := route.ValidateRequest(r, params)
+	if err != nil {
+		router.WriteProblem(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if len(violations) > 0 {
+		router.WriteProblem(w, http.StatusBadRequest, "request is invalid", violations)
+		return
+	}
+
+	prefer := parsePrefer(r.Header.Get("Prefer"))
+	status, response, err := s.selectResponse(route.Operation, prefer["code"])
+	if err != nil {
+		router.WriteProblem(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if response == nil {
+		// The document was modified after the server was created.
+		router.WriteProblem(w, http.StatusInternalServerError, fmt.Sprintf("response %d cannot be resolved", status), nil)
+		return
+	}
+
+	mediaType := selectMediaType(response.Content, r.Header.Get("Accept"))
+	if mediaType == "" || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.WriteHeader(status)
+		return
+	}
+	mt := response.Content[mediaType]
+	value, err := s.selectExample(mt, prefer["example"])
+	if err != nil {
+		router.WriteProblem(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	if str, ok := value.(string); ok && !router.IsJSONMediaType(mediaType) {
+		w.Write([]byte(str))
+		return
+	}
+	json.NewEncoder(w).Encode(value)
+}
+
+// parsePrefer parses the preferences of the Prefer header, e.g.
+// "code=404, example=NotFound".
+func parsePrefer(header string) map[string]string {
+	prefer := map[string]string{}
+	for _, token := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		parts := strings.SplitN(strings.TrimSpace(token), "=", 2)
+		if len(parts) == 2 {
+			prefer[strings.ToLower(parts[0])] = strings.Trim(parts[1], `"`)
+		}
+	}
+	return prefer
+}
+
+// selectResponse returns the response of the status code, or the first
+// successful response in alphabetical order if not specified. The response
+// is nil if it cannot be resolved.
+func (s *Server) selectResponse(op *openapi3.OperationObject, code string) (int, *openapi3.ResponseObject, error) {
+	codes := openapi3.SortedKeys(op.Responses)
+	var key string
+	if code != "" {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid preferred status code: %s", code)
+		}
+		for _, candidate := range []string{code, code[:1] + "XX", "default"} {
+			if _, ok := op.Responses[candidate]; ok {
+				key = candidate
+				break
+			}
+		}
+		if key == "" {
+			return 0, nil, fmt.Errorf("response %s is not declared", code)
+		}
+		return status, s.oapi.ResolveResponse(op.Responses[key]), nil
+	}
+
+	for _, candidate := range codes {
+		if strings.HasPrefix(candidate, "2") {
+			key = candidate
+			break
+		}
+	}
+	if key == "" {
+		if _, ok := op.Responses["default"]; ok {
+			key = "default"
+		} else if len(codes) > 0 {
+			key = codes[0]
+		} else {
+			return http.StatusOK, &openapi3.ResponseObject{}, nil
+		}
+	}
+	return statusOf(key), s.oapi.ResolveResponse(op.Responses[key]), nil
+}
+
+// statusOf returns the status code of a response key, e.g. 200 for "2XX".
+func statusOf(key string) int {
+	if status, err := strconv.Atoi(strings.Replace(strings.ToUpper(key), "XX", "00", 1)); err == nil {
+		return status
+	}
+	return http.StatusOK
+}
+
+// selectMediaType returns the media type of the content accepted by the
+// request, JSON, or the first media type.
+func selectMediaType(content map[string]openapi3.MediaTypeObject, accept string) string {
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil || mediaType == "*/*" {
+			continue
+		}
+		if _, ok := content[mediaType]; ok {
+			return mediaType
+		}
+	}
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	if mediaTypes := openapi3.SortedKeys(content); len(mediaTypes) > 0 {
+		return mediaTypes[0]
+	}
+	return ""
+}
+
+// selectExample returns the value of the named example, the first example
+// in alphabetical order, or an example generated from the schema if the
+// media type has no examples.
+func (s *Server) selectExample(mt openapi3.MediaTypeObject, name string) (interface{}, error) {
+	if name != "" {
+		example, ok := mt.Examples[name]
+		if !ok {
+			return nil, fmt.Errorf("example %s is not declared", name)
+		}
+		return example.Value, nil
+	}
+	if names := openapi3.SortedKeys(mt.Examples); len(names) > 0 {
+		return mt.Examples[names[0]].Value, nil
+	}
+	if mt.Example != nil {
+		return mt.Example, nil
+	}
+	return examples.Generate(s.oapi, mt.Schema), nil
+}
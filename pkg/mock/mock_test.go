@@ -0,0 +1,116 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+servers:
+- url: https://api.example.com/v1
+paths:
+  /users/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: integer}}
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+              examples:
+                Admin: {value: {id: 1, name: Admin}}
+                Test: {value: {id: 2, name: Test}}
+        "401": {$ref: '#/components/responses/Unauthorized'}
+        "404":
+          description: Not found
+          content:
+            application/json:
+              schema: {type: object, properties: {message: {type: string}}}
+    delete:
+      responses:
+        "204": {description: Deleted}
+        default: {description: Error}
+components:
+  responses:
+    Unauthorized: {description: Unauthorized}
+  schemas:
+    User:
+      type: object
+      properties:
+        id: {type: integer}
+        name: {type: string}
+`
+
+func TestServer(t *testing.T) {
+	Convey("Server", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+		s, err := New(oapi)
+		So(err, ShouldBeNil)
+
+		serve := func(method string, path string, prefer string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, nil)
+			if prefer != "" {
+				req.Header.Set("Prefer", prefer)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, req)
+			return w
+		}
+
+		Convey("should respond first example of first successful response", func() {
+			w := serve("GET", "/users/1", "")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+			So(w.Body.String(), ShouldEqual, `{"id":1,"name":"Admin"}`+"\n")
+
+			w = serve("GET", "/v1/users/1", "")
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("should select response and example with Prefer header", func() {
+			w := serve("GET", "/users/1", "example=Test")
+			So(w.Body.String(), ShouldEqual, `{"id":2,"name":"Test"}`+"\n")
+
+			w = serve("GET", "/users/1", "code=404")
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(w.Body.String(), ShouldEqual, `{"message":"string"}`+"\n")
+
+			w = serve("DELETE", "/users/1", "")
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(w.Body.String(), ShouldEqual, "")
+
+			w = serve("DELETE", "/users/1", "code=500")
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+
+			w = serve("GET", "/users/1", "code=500")
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(w.Body.String(), ShouldContainSubstring, `"detail":"response 500 is not declared"`)
+		})
+
+		Convey("should report unresolvable responses", func() {
+			delete(oapi.Components.Responses, "Unauthorized")
+			w := serve("GET", "/users/1", "code=401")
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(w.Body.String(), ShouldContainSubstring, `"detail":"response 401 cannot be resolved"`)
+		})
+
+		Convey("should report invalid requests", func() {
+			w := serve("GET", "/users/a", "")
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/problem+json")
+			So(w.Body.String(), ShouldContainSubstring, `"errors":["path id: must be integer, got string"]`)
+
+			So(serve("GET", "/posts", "").Code, ShouldEqual, http.StatusNotFound)
+			So(serve("POST", "/users/1", "").Code, ShouldEqual, http.StatusMethodNotAllowed)
+		})
+	})
+}
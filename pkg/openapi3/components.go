@@ -7,6 +7,13 @@ type ComponentsObject struct {
 	Responses       map[string]*ResponseObject       `yaml:"responses,omitempty"`
 	SecuritySchemes map[string]*SecuritySchemeObject `yaml:"securitySchemes,omitempty"`
 	Callbacks       map[string]*CallbackObject       `yaml:"callbacks,omitempty"`
+	PathItems       map[string]*PathItemObject       `yaml:"pathItems,omitempty"`
+	// Examples, Headers and Links are kept as generic documents.
+	Examples map[string]interface{} `yaml:"examples,omitempty"`
+	Headers  map[string]interface{} `yaml:"headers,omitempty"`
+	Links    map[string]interface{} `yaml:"links,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 func NewComponentsObject() *ComponentsObject {
@@ -17,5 +24,6 @@ func NewComponentsObject() *ComponentsObject {
 		Responses:       map[string]*ResponseObject{},
 		SecuritySchemes: map[string]*SecuritySchemeObject{},
 		Callbacks:       map[string]*CallbackObject{},
+		PathItems:       map[string]*PathItemObject{},
 	}
 }
@@ -0,0 +1,88 @@
+package openapi3
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ToDocument converts a value to its generic document form, composed of
+// map[string]interface{}, []interface{} and scalar values, as it would be
+// serialized.
+func ToDocument(v interface{}) (interface{}, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return ReadDocument(data)
+}
+
+// ReadDocument parses a YAML or JSON document to its generic document form.
+func ReadDocument(data []byte) (interface{}, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return normalizeDocument(doc), nil
+}
+
+// NormalizeValue converts a value decoded from YAML to its generic document
+// form, copying objects and arrays.
+func NormalizeValue(value interface{}) interface{} {
+	return normalizeDocument(value)
+}
+
+func normalizeDocument(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			obj[fmt.Sprint(key)] = normalizeDocument(value)
+		}
+		return obj
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			obj[key] = normalizeDocument(value)
+		}
+		return obj
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, value := range v {
+			arr[i] = normalizeDocument(value)
+		}
+		return arr
+	default:
+		return value
+	}
+}
+
+var pointerTokenEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Pointer returns the JSON pointer of the reference tokens, e.g.
+// "/paths/~1users/get".
+func Pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(pointerTokenEscaper.Replace(token))
+	}
+	return b.String()
+}
+
+// SortedKeys returns the sorted keys of a map with string keys.
+func SortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map {
+		panic(fmt.Errorf("not a map: %T", m))
+	}
+	var keys []string
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,7 +1,10 @@
 package openapi3
 
 type ExampleObject struct {
-	Summary     string      `yaml:"summary,omitempty"`
-	Description string      `yaml:"description,omitempty"`
-	Value       interface{} `yaml:"value,omitempty"`
+	Summary       string      `yaml:"summary,omitempty"`
+	Description   string      `yaml:"description,omitempty"`
+	Value         interface{} `yaml:"value,omitempty"`
+	ExternalValue string      `yaml:"externalValue,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
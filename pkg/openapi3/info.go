@@ -1,7 +1,30 @@
 package openapi3
 
 type InfoObject struct {
-	Title       string `yaml:"title"`
-	Description string `yaml:"description,omitempty"`
-	Version     string `yaml:"version,omitempty"`
+	Title          string         `yaml:"title"`
+	Summary        string         `yaml:"summary,omitempty"`
+	Description    string         `yaml:"description,omitempty"`
+	TermsOfService string         `yaml:"termsOfService,omitempty"`
+	Contact        *ContactObject `yaml:"contact,omitempty"`
+	License        *LicenseObject `yaml:"license,omitempty"`
+	Version        string         `yaml:"version,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+type ContactObject struct {
+	Name  string `yaml:"name,omitempty"`
+	URL   string `yaml:"url,omitempty"`
+	Email string `yaml:"email,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+type LicenseObject struct {
+	Name string `yaml:"name"`
+	// Identifier is the SPDX license expression, since OpenAPI 3.1.
+	Identifier string `yaml:"identifier,omitempty"`
+	URL        string `yaml:"url,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
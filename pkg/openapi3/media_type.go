@@ -2,7 +2,12 @@ package openapi3
 
 type MediaTypeObject struct {
 	Schema   Schema                   `yaml:"schema,omitempty"`
+	Example  interface{}              `yaml:"example,omitempty"`
 	Examples map[string]ExampleObject `yaml:"examples,omitempty"`
+	// Encoding is kept as a generic document.
+	Encoding map[string]interface{} `yaml:"encoding,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 func NewMediaTypeObject() *MediaTypeObject {
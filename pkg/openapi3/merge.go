@@ -0,0 +1,250 @@
+package openapi3
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MergeStrategy determines how conflicting definitions are merged.
+type MergeStrategy string
+
+const (
+	// MergeStrategyError fails the merge on conflicting definitions.
+	MergeStrategyError MergeStrategy = "error"
+	// MergeStrategyPreferBase keeps definitions of the base document.
+	MergeStrategyPreferBase MergeStrategy = "prefer-base"
+	// MergeStrategyPreferSource takes definitions of the merged document.
+	MergeStrategyPreferSource MergeStrategy = "prefer-source"
+)
+
+func (s MergeStrategy) Validate() bool {
+	return s == MergeStrategyError ||
+		s == MergeStrategyPreferBase ||
+		s == MergeStrategyPreferSource
+}
+
+// MergeError lists the locations of conflicting definitions of a merge.
+type MergeError struct {
+	Conflicts []string
+}
+
+func (err MergeError) Error() string {
+	return "conflicting definitions: " + strings.Join(err.Conflicts, ", ")
+}
+
+type merger struct {
+	strategy  MergeStrategy
+	conflicts []string
+}
+
+// resolve returns whether to take the source definition of a value defined in
+// both documents.
+func (m *merger) resolve(location string, base, source interface{}) bool {
+	if equalObjects(base, source) {
+		return false
+	}
+	switch m.strategy {
+	case MergeStrategyPreferBase:
+		return false
+	case MergeStrategyPreferSource:
+		return true
+	default:
+		m.conflicts = append(m.conflicts, location)
+		return false
+	}
+}
+
+func (m *merger) mergeString(location string, base *string, source string) {
+	if source == "" {
+		return
+	}
+	if *base == "" || m.resolve(location, *base, source) {
+		*base = source
+	}
+}
+
+// mergeExtensions merges maps of extensions or generic documents by key.
+func (m *merger) mergeExtensions(location string, base *map[string]interface{}, source map[string]interface{}) {
+	for _, key := range SortedKeys(source) {
+		value := source[key]
+		existing, exists := (*base)[key]
+		if exists && !m.resolve(location+Pointer(key), existing, value) {
+			continue
+		}
+		if *base == nil {
+			*base = map[string]interface{}{}
+		}
+		(*base)[key] = value
+	}
+}
+
+// Merge merges the definitions of source into the document, e.g. a document
+// generated from annotations into a hand-maintained base document.
+// Definitions in both documents are merged according to the strategy, except
+// that info of the document is kept unless source is preferred.
+func (oapi *OpenAPIObject) Merge(source *OpenAPIObject, strategy MergeStrategy) error {
+	m := &merger{strategy: strategy}
+
+	info := &merger{strategy: strategy}
+	if strategy == MergeStrategyError {
+		info.strategy = MergeStrategyPreferBase
+	}
+	info.mergeString("/info/title", &oapi.Info.Title, source.Info.Title)
+	info.mergeString("/info/summary", &oapi.Info.Summary, source.Info.Summary)
+	info.mergeString("/info/description", &oapi.Info.Description, source.Info.Description)
+	info.mergeString("/info/termsOfService", &oapi.Info.TermsOfService, source.Info.TermsOfService)
+	if source.Info.Contact != nil {
+		if oapi.Info.Contact == nil || info.resolve("/info/contact", oapi.Info.Contact, source.Info.Contact) {
+			oapi.Info.Contact = source.Info.Contact
+		}
+	}
+	if source.Info.License != nil {
+		if oapi.Info.License == nil || info.resolve("/info/license", oapi.Info.License, source.Info.License) {
+			oapi.Info.License = source.Info.License
+		}
+	}
+	info.mergeString("/info/version", &oapi.Info.Version, source.Info.Version)
+	info.mergeExtensions("/info", &oapi.Info.Extensions, source.Info.Extensions)
+
+	m.mergeString("/jsonSchemaDialect", &oapi.JSONSchemaDialect, source.JSONSchemaDialect)
+	if source.ExternalDocs != nil {
+		if oapi.ExternalDocs == nil || m.resolve("/externalDocs", oapi.ExternalDocs, source.ExternalDocs) {
+			oapi.ExternalDocs = source.ExternalDocs
+		}
+	}
+
+	for _, server := range source.Servers {
+		i := indexOf(len(oapi.Servers), func(i int) bool { return oapi.Servers[i].URL == server.URL })
+		if i < 0 {
+			oapi.Servers = append(oapi.Servers, server)
+		} else if m.resolve(Pointer("servers", server.URL), oapi.Servers[i], server) {
+			oapi.Servers[i] = server
+		}
+	}
+
+	for _, tag := range source.Tags {
+		i := indexOf(len(oapi.Tags), func(i int) bool { return oapi.Tags[i].Name == tag.Name })
+		if i < 0 {
+			oapi.Tags = append(oapi.Tags, tag)
+		} else if m.resolve(Pointer("tags", tag.Name), oapi.Tags[i], tag) {
+			oapi.Tags[i] = tag
+		}
+	}
+
+	for _, requirement := range source.Security {
+		i := indexOf(len(oapi.Security), func(i int) bool { return equalObjects(oapi.Security[i], requirement) })
+		if i < 0 {
+			oapi.Security = append(oapi.Security, requirement)
+		}
+	}
+
+	if oapi.Paths == nil {
+		oapi.Paths = PathsObject{}
+	}
+	for _, path := range SortedKeys(source.Paths) {
+		sourceItem := source.Paths[path]
+		baseItem, exists := oapi.Paths[path]
+		if !exists {
+			oapi.Paths[path] = sourceItem
+			continue
+		}
+		m.mergePathItem(Pointer("paths", path), &baseItem, sourceItem)
+		oapi.Paths[path] = baseItem
+	}
+
+	for _, name := range SortedKeys(source.Webhooks) {
+		sourceItem := source.Webhooks[name]
+		baseItem, exists := oapi.Webhooks[name]
+		if !exists {
+			if oapi.Webhooks == nil {
+				oapi.Webhooks = WebhooksObject{}
+			}
+			oapi.Webhooks[name] = sourceItem
+			continue
+		}
+		m.mergePathItem(Pointer("webhooks", name), &baseItem, sourceItem)
+		oapi.Webhooks[name] = baseItem
+	}
+
+	oapi.Components.init()
+	mergeComponents(m, "/components/schemas", oapi.Components.Schemas, source.Components.Schemas)
+	mergeComponents(m, "/components/parameters", oapi.Components.Parameters, source.Components.Parameters)
+	mergeComponents(m, "/components/requestBodies", oapi.Components.RequestBodies, source.Components.RequestBodies)
+	mergeComponents(m, "/components/responses", oapi.Components.Responses, source.Components.Responses)
+	mergeComponents(m, "/components/securitySchemes", oapi.Components.SecuritySchemes, source.Components.SecuritySchemes)
+	mergeComponents(m, "/components/callbacks", oapi.Components.Callbacks, source.Components.Callbacks)
+	mergeComponents(m, "/components/pathItems", oapi.Components.PathItems, source.Components.PathItems)
+	m.mergeExtensions("/components/examples", &oapi.Components.Examples, source.Components.Examples)
+	m.mergeExtensions("/components/headers", &oapi.Components.Headers, source.Components.Headers)
+	m.mergeExtensions("/components/links", &oapi.Components.Links, source.Components.Links)
+	m.mergeExtensions("/components", &oapi.Components.Extensions, source.Components.Extensions)
+
+	m.mergeExtensions("", &oapi.Extensions, source.Extensions)
+
+	if len(m.conflicts) > 0 {
+		return MergeError{Conflicts: m.conflicts}
+	}
+	return nil
+}
+
+func (m *merger) mergePathItem(location string, base *PathItemObject, source PathItemObject) {
+	m.mergeString(location+"/$ref", &base.Ref, source.Ref)
+	m.mergeString(location+"/summary", &base.Summary, source.Summary)
+	m.mergeString(location+"/description", &base.Description, source.Description)
+	if len(source.Parameters) > 0 {
+		if len(base.Parameters) == 0 || m.resolve(location+"/parameters", base.Parameters, source.Parameters) {
+			base.Parameters = source.Parameters
+		}
+	}
+
+	for _, method := range Methods {
+		sourceOp := source.GetOperation(method)
+		if sourceOp == nil {
+			continue
+		}
+		baseOp := base.GetOperation(method)
+		if baseOp == nil || m.resolve(location+Pointer(strings.ToLower(method)), baseOp, sourceOp) {
+			base.SetOperation(method, sourceOp)
+		}
+	}
+	if len(source.Servers) > 0 {
+		if len(base.Servers) == 0 || m.resolve(location+"/servers", base.Servers, source.Servers) {
+			base.Servers = source.Servers
+		}
+	}
+
+	m.mergeExtensions(location, &base.Extensions, source.Extensions)
+}
+
+// mergeComponents merges component maps of the same type.
+func mergeComponents(m *merger, location string, base interface{}, source interface{}) {
+	baseMap, sourceMap := reflect.ValueOf(base), reflect.ValueOf(source)
+	for _, key := range SortedKeys(source) {
+		keyValue := reflect.ValueOf(key)
+		sourceValue := sourceMap.MapIndex(keyValue)
+		baseValue := baseMap.MapIndex(keyValue)
+		if !baseValue.IsValid() || m.resolve(location+Pointer(key), baseValue.Interface(), sourceValue.Interface()) {
+			baseMap.SetMapIndex(keyValue, sourceValue)
+		}
+	}
+}
+
+// equalObjects returns whether the objects are serialized to the same
+// document.
+func equalObjects(a, b interface{}) bool {
+	docA, errA := ToDocument(a)
+	docB, errB := ToDocument(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return reflect.DeepEqual(docA, docB)
+}
+
+func indexOf(n int, match func(i int) bool) int {
+	for i := 0; i < n; i++ {
+		if match(i) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,190 @@
+package openapi3
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.1
+info: {title: Test API, version: 1.0.0, x-logo: {url: logo.png}}
+x-gateway: {timeout: 30}
+paths:
+  /users:
+    $ref: '#/components/pathItems/Users'
+  /users/{id}:
+    get:
+      x-rate-limit: 10
+      deprecated: true
+      security: []
+      parameters:
+      - $ref: '#/components/parameters/ID'
+      - {name: fields, in: query, schema: {type: string}}
+      requestBody: {$ref: '#/components/requestBodies/Body'}
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+              examples: {user: {value: {name: Test}}}
+        default: {$ref: '#/components/responses/Error'}
+      callbacks:
+        updated: {'{$request.body#/url}': {post: {responses: {'200': {description: OK}}}}}
+components:
+  schemas:
+    User: {type: object, properties: {name: {type: string}}}
+  parameters:
+    ID: {name: id, in: path, required: true, schema: {type: string}}
+  requestBodies:
+    Body: {content: {application/json: {schema: {type: object}}}}
+  responses:
+    Error: {description: Error}
+  securitySchemes:
+    oauth: {type: oauth2, flows: {implicit: {authorizationUrl: 'https://example.com/', scopes: {}}}}
+`
+
+func TestLoad(t *testing.T) {
+	Convey("Load", t, func() {
+		oapi, err := Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+
+		op := oapi.Paths["/users/{id}"].Get
+		So(op.Parameters[0], ShouldResemble, MakeParameterRef("ID"))
+		So(op.Parameters[1].(*ParameterObject).Schema, ShouldResemble, map[string]interface{}{"type": "string"})
+		So(op.RequestBody, ShouldResemble, MakeRequestBodyRef("Body"))
+		So(op.Responses["200"].(*ResponseObject).Content["application/json"].Schema, ShouldResemble, MakeSchemaRef("User"))
+		So(op.Responses["200"].(*ResponseObject).Content["application/json"].Examples["user"].Value, ShouldResemble, map[string]interface{}{"name": "Test"})
+		So(op.Responses["default"], ShouldResemble, MakeResponseRef("Error"))
+		So(op.Callbacks["updated"], ShouldHaveSameTypeAs, &CallbackObject{})
+		So(op.Security, ShouldNotBeNil)
+		So(op.Security, ShouldBeEmpty)
+		So(oapi.Security, ShouldBeNil)
+		So(op.Deprecated, ShouldBeTrue)
+		So(op.Extensions, ShouldResemble, map[string]interface{}{"x-rate-limit": 10})
+		So(oapi.Paths["/users"].Ref, ShouldEqual, "#/components/pathItems/Users")
+		So(oapi.Extensions, ShouldResemble, map[string]interface{}{"x-gateway": map[string]interface{}{"timeout": 30}})
+
+		expected, err := ReadDocument([]byte(testDocument))
+		So(err, ShouldBeNil)
+		doc, err := ToDocument(oapi)
+		So(err, ShouldBeNil)
+		So(doc, ShouldResemble, expected)
+
+		_, err = Load([]byte("openapi: 3.0.0\npaths:\n  /users:\n    get:\n      responses: {}\n      deprecate: true\n"))
+		So(err, ShouldBeError, `unknown field "deprecate" in operation object`)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	Convey("Merge", t, func() {
+		source := NewOpenAPIObject()
+		source.Info.Title = "Generated API"
+		source.Info.Version = "1.0.0"
+		getUser := NewOperationObject()
+		getUser.Summary = "Get user"
+		createUser := NewOperationObject()
+		createUser.Summary = "Create user"
+		source.Paths["/users/{id}"] = PathItemObject{Get: getUser}
+		source.Paths["/users"] = PathItemObject{Post: createUser}
+		var userSchema Schema = map[string]interface{}{"type": "object"}
+		source.Components.Schemas["User"] = &userSchema
+
+		merge := func(strategy MergeStrategy) (*OpenAPIObject, error) {
+			base, err := Load([]byte(testDocument))
+			So(err, ShouldBeNil)
+			return base, base.Merge(source, strategy)
+		}
+
+		Convey("should report conflicts", func() {
+			_, err := merge(MergeStrategyError)
+			So(err, ShouldResemble, MergeError{Conflicts: []string{
+				"/paths/~1users~1{id}/get",
+				"/components/schemas/User",
+			}})
+		})
+
+		Convey("should keep base info", func() {
+			source.Paths = PathsObject{}
+			source.Components.Schemas = map[string]*Schema{}
+			oapi, err := merge(MergeStrategyError)
+			So(err, ShouldBeNil)
+			So(oapi.Info.Title, ShouldEqual, "Test API")
+		})
+
+		Convey("should prefer base definitions", func() {
+			oapi, err := merge(MergeStrategyPreferBase)
+			So(err, ShouldBeNil)
+			So(oapi.Info.Title, ShouldEqual, "Test API")
+			So(oapi.Paths["/users/{id}"].Get.Extensions["x-rate-limit"], ShouldEqual, 10)
+			So(oapi.Paths["/users"].Post, ShouldEqual, createUser)
+			So(oapi.Extensions["x-gateway"], ShouldNotBeNil)
+		})
+
+		Convey("should prefer source definitions", func() {
+			oapi, err := merge(MergeStrategyPreferSource)
+			So(err, ShouldBeNil)
+			So(oapi.Info.Title, ShouldEqual, "Generated API")
+			So(oapi.Paths["/users/{id}"].Get, ShouldEqual, getUser)
+			So(*oapi.Components.Schemas["User"], ShouldResemble, userSchema)
+			So(oapi.Components.Parameters["ID"], ShouldNotBeNil)
+		})
+	})
+}
+
+func TestConvertSchema(t *testing.T) {
+	Convey("ConvertSchema", t, func() {
+		read := func(doc string) interface{} {
+			value, err := ReadDocument([]byte(doc))
+			So(err, ShouldBeNil)
+			return value
+		}
+
+		Convey("should convert to OpenAPI 3.1", func() {
+			schema := read(`
+type: object
+properties:
+  status: {type: string, enum: [active, disabled], nullable: true}
+  owner: {allOf: [{$ref: '#/components/schemas/User'}], nullable: true}
+  tags: {type: array, items: {type: string, example: admin}}
+  score: {type: number, maximum: 100, exclusiveMaximum: false}
+`)
+			So(ConvertSchema(schema, Version31), ShouldResemble, read(`
+type: object
+properties:
+  status: {type: [string, "null"], enum: [active, disabled, null]}
+  owner:
+    anyOf:
+    - {allOf: [{$ref: '#/components/schemas/User'}]}
+    - {type: "null"}
+  tags: {type: array, items: {type: string, examples: [admin]}}
+  score: {type: number, maximum: 100}
+`))
+		})
+
+		Convey("should convert to OpenAPI 3.0", func() {
+			schema := read(`
+type: [object, "null"]
+properties:
+  id: {type: [string, integer]}
+  score: {type: number, exclusiveMinimum: 0, examples: [1, 2]}
+  kind: {const: user}
+`)
+			So(ConvertSchema(schema, Version30), ShouldResemble, read(`
+type: object
+nullable: true
+properties:
+  id: {anyOf: [{type: string}, {type: integer}]}
+  score: {type: number, minimum: 0, exclusiveMinimum: true, example: 1}
+  kind: {enum: [user]}
+`))
+		})
+
+		Convey("should not modify the schema", func() {
+			schema := read(`{type: string, nullable: true}`)
+			ConvertSchema(schema, Version31)
+			So(schema, ShouldResemble, read(`{type: string, nullable: true}`))
+		})
+	})
+}
@@ -1,15 +1,20 @@
 package openapi3
 
 type OperationObject struct {
-	Tags        []string                    `yaml:"tags,omitempty"`
-	Summary     string                      `yaml:"summary,omitempty"`
-	Description string                      `yaml:"description,omitempty"`
-	ID          string                      `yaml:"operationId,omitempty"`
-	Parameters  []Parameter                 `yaml:"parameters,omitempty"`
-	RequestBody RequestBody                 `yaml:"requestBody,omitempty"`
-	Responses   map[string]Response         `yaml:"responses"`
-	Callbacks   map[string]Callback         `yaml:"callbacks,omitempty"`
-	Security    []SecurityRequirementObject `yaml:"security,omitempty"`
+	Tags         []string                     `yaml:"tags,omitempty"`
+	Summary      string                       `yaml:"summary,omitempty"`
+	Description  string                       `yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocumentationObject `yaml:"externalDocs,omitempty"`
+	ID           string                       `yaml:"operationId,omitempty"`
+	Parameters   []Parameter                  `yaml:"parameters,omitempty"`
+	RequestBody  RequestBody                  `yaml:"requestBody,omitempty"`
+	Responses    map[string]Response          `yaml:"responses"`
+	Callbacks    map[string]Callback          `yaml:"callbacks,omitempty"`
+	Deprecated   bool                         `yaml:"deprecated,omitempty"`
+	Security     SecurityRequirements         `yaml:"security,omitempty"`
+	Servers      []ServerObject               `yaml:"servers,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 func NewOperationObject() *OperationObject {
@@ -18,12 +18,21 @@ func (l ParameterLocation) Validate() bool {
 
 type Parameter interface{}
 type ParameterObject struct {
-	Name        string                   `yaml:"name"`
-	Location    ParameterLocation        `yaml:"in"`
-	Description string                   `yaml:"description,omitempty"`
-	Required    bool                     `yaml:"required,omitempty"`
-	Schema      Schema                   `yaml:"schema,omitempty"`
-	Examples    map[string]ExampleObject `yaml:"examples,omitempty"`
+	Name            string                     `yaml:"name"`
+	Location        ParameterLocation          `yaml:"in"`
+	Description     string                     `yaml:"description,omitempty"`
+	Required        bool                       `yaml:"required,omitempty"`
+	Deprecated      bool                       `yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool                       `yaml:"allowEmptyValue,omitempty"`
+	Style           string                     `yaml:"style,omitempty"`
+	Explode         *bool                      `yaml:"explode,omitempty"`
+	AllowReserved   bool                       `yaml:"allowReserved,omitempty"`
+	Schema          Schema                     `yaml:"schema,omitempty"`
+	Example         interface{}                `yaml:"example,omitempty"`
+	Examples        map[string]ExampleObject   `yaml:"examples,omitempty"`
+	Content         map[string]MediaTypeObject `yaml:"content,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 func NewParameterObject() *ParameterObject {
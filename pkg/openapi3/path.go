@@ -21,7 +21,22 @@ func (paths *PathsObject) GetPath(path string) PathItemObject {
 	return (*paths)[path]
 }
 
+// Methods are the HTTP methods of operations in path items, in the order of
+// fields in PathItemObject.
+var Methods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+	http.MethodTrace,
+}
+
 type PathItemObject struct {
+	// Ref is the reference to a path item defined elsewhere.
+	Ref         string            `yaml:"$ref,omitempty"`
 	Summary     string            `yaml:"summary,omitempty"`
 	Description string            `yaml:"description,omitempty"`
 	Parameters  []ParameterObject `yaml:"parameters,omitempty"`
@@ -33,6 +48,9 @@ type PathItemObject struct {
 	Head        *OperationObject  `yaml:"head,omitempty"`
 	Patch       *OperationObject  `yaml:"patch,omitempty"`
 	Trace       *OperationObject  `yaml:"trace,omitempty"`
+	Servers     []ServerObject    `yaml:"servers,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 func (path *PathItemObject) SetOperation(method string, op *OperationObject) bool {
@@ -58,3 +76,26 @@ func (path *PathItemObject) SetOperation(method string, op *OperationObject) boo
 	}
 	return true
 }
+
+func (path *PathItemObject) GetOperation(method string) *OperationObject {
+	switch method {
+	case http.MethodGet:
+		return path.Get
+	case http.MethodPut:
+		return path.Put
+	case http.MethodPost:
+		return path.Post
+	case http.MethodDelete:
+		return path.Delete
+	case http.MethodOptions:
+		return path.Options
+	case http.MethodHead:
+		return path.Head
+	case http.MethodPatch:
+		return path.Patch
+	case http.MethodTrace:
+		return path.Trace
+	default:
+		return nil
+	}
+}
@@ -5,6 +5,8 @@ type RequestBodyObject struct {
 	Description string                     `yaml:"description,omitempty"`
 	Content     map[string]MediaTypeObject `yaml:"content"`
 	Required    bool                       `yaml:"required,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 func NewRequestBodyObject() *RequestBodyObject {
@@ -0,0 +1,83 @@
+package openapi3
+
+import "strings"
+
+// RefID returns the component ID of a reference object with the specified
+// reference prefix, e.g. "#/components/schemas/".
+func RefID(obj interface{}, prefix string) (string, bool) {
+	m, ok := SchemaMap(obj)
+	if !ok {
+		return "", false
+	}
+	ref, ok := m["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(ref, prefix), true
+}
+
+// SchemaMap returns the JSON object of a schema or reference object.
+func SchemaMap(schema interface{}) (map[string]interface{}, bool) {
+	switch typedSchema := schema.(type) {
+	case map[string]interface{}:
+		return typedSchema, true
+	case ReferenceObject:
+		return typedSchema, true
+	case *Schema:
+		if typedSchema == nil {
+			return nil, false
+		}
+		return SchemaMap(*typedSchema)
+	default:
+		return nil, false
+	}
+}
+
+// ResolveSchema resolves component schema references. The ID of the
+// referenced component is returned if the schema is a reference.
+func (oapi *OpenAPIObject) ResolveSchema(schema Schema) (map[string]interface{}, string) {
+	id, isRef := RefID(schema, "#/components/schemas/")
+	if !isRef {
+		m, _ := SchemaMap(schema)
+		return m, ""
+	}
+
+	component, ok := oapi.Components.Schemas[id]
+	if !ok {
+		return nil, id
+	}
+	m, _ := SchemaMap(component)
+	return m, id
+}
+
+func (oapi *OpenAPIObject) ResolveParameter(param Parameter) *ParameterObject {
+	if id, ok := RefID(param, "#/components/parameters/"); ok {
+		return oapi.Components.Parameters[id]
+	}
+	paramObj, _ := param.(*ParameterObject)
+	return paramObj
+}
+
+func (oapi *OpenAPIObject) ResolveRequestBody(body RequestBody) *RequestBodyObject {
+	if id, ok := RefID(body, "#/components/requestBodies/"); ok {
+		return oapi.Components.RequestBodies[id]
+	}
+	bodyObj, _ := body.(*RequestBodyObject)
+	return bodyObj
+}
+
+func (oapi *OpenAPIObject) ResolveResponse(response Response) *ResponseObject {
+	if id, ok := RefID(response, "#/components/responses/"); ok {
+		return oapi.Components.Responses[id]
+	}
+	responseObj, _ := response.(*ResponseObject)
+	return responseObj
+}
+
+func (oapi *OpenAPIObject) ResolveCallback(callback Callback) *CallbackObject {
+	if id, ok := RefID(callback, "#/components/callbacks/"); ok {
+		return oapi.Components.Callbacks[id]
+	}
+	callbackObj, _ := callback.(*CallbackObject)
+	return callbackObj
+}
@@ -2,8 +2,13 @@ package openapi3
 
 type Response interface{}
 type ResponseObject struct {
-	Description string                     `yaml:"description"`
-	Content     map[string]MediaTypeObject `yaml:"content,omitempty"`
+	Description string `yaml:"description"`
+	// Headers and Links are kept as generic documents.
+	Headers map[string]interface{}     `yaml:"headers,omitempty"`
+	Content map[string]MediaTypeObject `yaml:"content,omitempty"`
+	Links   map[string]interface{}     `yaml:"links,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 func NewResponseObject() *ResponseObject {
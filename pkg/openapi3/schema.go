@@ -1,3 +1,175 @@
 package openapi3
 
 type Schema interface{}
+
+// subschemaKeywords are the keywords with a schema value.
+var subschemaKeywords = []string{"items", "additionalProperties", "not", "contains", "propertyNames", "if", "then", "else"}
+
+// subschemaArrayKeywords are the keywords with an array of schemas value.
+var subschemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+
+// subschemaMapKeywords are the keywords with a map of schemas value.
+var subschemaMapKeywords = []string{"properties", "patternProperties", "$defs", "definitions"}
+
+// ConvertSchema converts a schema in generic document form between the
+// OpenAPI 3.0 schema dialect and the JSON Schema 2020-12 dialect of OpenAPI
+// 3.1, according to the target version. The schema is not modified.
+//
+// For OpenAPI 3.1, nullable is converted to a null type, example to examples
+// and boolean exclusiveMinimum/exclusiveMaximum to numeric. For OpenAPI 3.0,
+// the conversions are reversed and const is converted to a single value enum.
+func ConvertSchema(schema interface{}, version string) interface{} {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	if _, isRef := obj["$ref"]; isRef {
+		return schema
+	}
+
+	result := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		result[key] = value
+	}
+	for _, keyword := range subschemaKeywords {
+		if value, exists := result[keyword]; exists {
+			result[keyword] = ConvertSchema(value, version)
+		}
+	}
+	for _, keyword := range subschemaArrayKeywords {
+		if values, ok := result[keyword].([]interface{}); ok {
+			converted := make([]interface{}, len(values))
+			for i, value := range values {
+				converted[i] = ConvertSchema(value, version)
+			}
+			result[keyword] = converted
+		}
+	}
+	for _, keyword := range subschemaMapKeywords {
+		if values, ok := result[keyword].(map[string]interface{}); ok {
+			converted := make(map[string]interface{}, len(values))
+			for key, value := range values {
+				converted[key] = ConvertSchema(value, version)
+			}
+			result[keyword] = converted
+		}
+	}
+
+	if IsVersion31(version) {
+		return convertSchemaTo31(result)
+	}
+	return convertSchemaTo30(result)
+}
+
+func convertSchemaTo31(schema map[string]interface{}) map[string]interface{} {
+	if example, exists := schema["example"]; exists {
+		if _, hasExamples := schema["examples"]; !hasExamples {
+			schema["examples"] = []interface{}{example}
+		}
+		delete(schema, "example")
+	}
+
+	for _, bound := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+		exclusive, isBool := schema[bound[0]].(bool)
+		if !isBool {
+			continue
+		}
+		delete(schema, bound[0])
+		if limit, exists := schema[bound[1]]; exists && exclusive {
+			schema[bound[0]] = limit
+			delete(schema, bound[1])
+		}
+	}
+
+	nullable, _ := schema["nullable"].(bool)
+	delete(schema, "nullable")
+	if !nullable {
+		return schema
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsNull(enum) {
+		schema["enum"] = append(enum[:len(enum):len(enum)], nil)
+	}
+	switch t := schema["type"].(type) {
+	case string:
+		schema["type"] = []interface{}{t, "null"}
+	case []interface{}:
+		if !containsString(t, "null") {
+			schema["type"] = append(t[:len(t):len(t)], "null")
+		}
+	default:
+		return map[string]interface{}{
+			"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}},
+		}
+	}
+	return schema
+}
+
+func convertSchemaTo30(schema map[string]interface{}) map[string]interface{} {
+	if examples, ok := schema["examples"].([]interface{}); ok {
+		if _, hasExample := schema["example"]; !hasExample && len(examples) > 0 {
+			schema["example"] = examples[0]
+		}
+		delete(schema, "examples")
+	}
+
+	if value, exists := schema["const"]; exists {
+		if _, hasEnum := schema["enum"]; !hasEnum {
+			schema["enum"] = []interface{}{value}
+		}
+		delete(schema, "const")
+	}
+
+	for _, bound := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+		limit, exists := schema[bound[0]]
+		if _, isBool := limit.(bool); !exists || isBool {
+			continue
+		}
+		schema[bound[0]] = true
+		schema[bound[1]] = limit
+	}
+
+	types, ok := schema["type"].([]interface{})
+	if !ok {
+		return schema
+	}
+	var nonNull []interface{}
+	for _, t := range types {
+		if t == "null" {
+			schema["nullable"] = true
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+	switch len(nonNull) {
+	case 0:
+		delete(schema, "type")
+	case 1:
+		schema["type"] = nonNull[0]
+	default:
+		delete(schema, "type")
+		var anyOf []interface{}
+		for _, t := range nonNull {
+			anyOf = append(anyOf, map[string]interface{}{"type": t})
+		}
+		schema["anyOf"] = anyOf
+	}
+	return schema
+}
+
+func containsNull(values []interface{}) bool {
+	for _, value := range values {
+		if value == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []interface{}, str string) bool {
+	for _, value := range values {
+		if value == str {
+			return true
+		}
+	}
+	return false
+}
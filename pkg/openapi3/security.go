@@ -32,6 +32,20 @@ type SecuritySchemeObject struct {
 	APIKeyLocation   SecuritySchemeAPIKeyLocation `yaml:"in,omitempty"`
 	HTTPAuthScheme   string                       `yaml:"scheme,omitempty"`
 	HTTPBearerFormat string                       `yaml:"bearerFormat,omitempty"`
+	// Flows is the OAuth flows, kept as a generic document.
+	Flows            interface{} `yaml:"flows,omitempty"`
+	OpenIDConnectURL string      `yaml:"openIdConnectUrl,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 type SecurityRequirementObject map[string][]string
+
+// SecurityRequirements are alternative security requirements. An empty
+// non-nil list, e.g. `security: []` in an operation, removes the security
+// requirements of the specification, and is preserved when marshaling.
+type SecurityRequirements []SecurityRequirementObject
+
+func (r SecurityRequirements) IsZero() bool {
+	return r == nil
+}
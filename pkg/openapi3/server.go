@@ -4,6 +4,8 @@ type ServerObject struct {
 	URL         string                    `yaml:"url"`
 	Description string                    `yaml:"description,omitempty"`
 	Variables   map[string]ServerVariable `yaml:"variables,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 type ServerVariable struct {
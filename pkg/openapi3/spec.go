@@ -1,18 +1,24 @@
 package openapi3
 
 type OpenAPIObject struct {
-	Version    string                      `yaml:"openapi"`
-	Info       InfoObject                  `yaml:"info"`
-	Servers    []ServerObject              `yaml:"servers,omitempty"`
-	Paths      PathsObject                 `yaml:"paths"`
-	Components ComponentsObject            `yaml:"components,omitempty"`
-	Security   []SecurityRequirementObject `yaml:"security,omitempty"`
-	Tags       []TagObject                 `yaml:"tags,omitempty"`
+	Version string     `yaml:"openapi"`
+	Info    InfoObject `yaml:"info"`
+	// JSONSchemaDialect is the default $schema of schemas, since OpenAPI 3.1.
+	JSONSchemaDialect string                       `yaml:"jsonSchemaDialect,omitempty"`
+	Servers           []ServerObject               `yaml:"servers,omitempty"`
+	Paths             PathsObject                  `yaml:"paths"`
+	Webhooks          WebhooksObject               `yaml:"webhooks,omitempty"`
+	Components        ComponentsObject             `yaml:"components,omitempty"`
+	Security          SecurityRequirements         `yaml:"security,omitempty"`
+	Tags              []TagObject                  `yaml:"tags,omitempty"`
+	ExternalDocs      *ExternalDocumentationObject `yaml:"externalDocs,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
 func NewOpenAPIObject() *OpenAPIObject {
 	return &OpenAPIObject{
-		Version:    "3.0.0",
+		Version:    Version30,
 		Paths:      *NewPathsObject(),
 		Components: *NewComponentsObject(),
 	}
@@ -1,6 +1,16 @@
 package openapi3
 
 type TagObject struct {
-	Name        string `yaml:"name"`
+	Name         string                       `yaml:"name"`
+	Description  string                       `yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocumentationObject `yaml:"externalDocs,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+type ExternalDocumentationObject struct {
 	Description string `yaml:"description,omitempty"`
+	URL         string `yaml:"url"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
 }
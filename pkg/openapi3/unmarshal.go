@@ -0,0 +1,364 @@
+package openapi3
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Load parses an OpenAPI document in YAML or JSON.
+func Load(data []byte) (*OpenAPIObject, error) {
+	oapi := NewOpenAPIObject()
+	if err := yaml.Unmarshal(data, oapi); err != nil {
+		return nil, err
+	}
+	if oapi.Paths == nil {
+		oapi.Paths = *NewPathsObject()
+	}
+	oapi.Components.init()
+	return oapi, nil
+}
+
+// decodeValue decodes a generic YAML value into out.
+func decodeValue(value interface{}, out interface{}) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+// decodeReference decodes a generic YAML value as a reference object, if it
+// has a $ref property.
+func decodeReference(value interface{}) (ReferenceObject, bool) {
+	obj, ok := normalizeDocument(value).(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if _, isRef := obj["$ref"]; !isRef {
+		return nil, false
+	}
+	return ReferenceObject(obj), true
+}
+
+func decodeSchema(value interface{}) Schema {
+	if value == nil {
+		return nil
+	}
+	if ref, ok := decodeReference(value); ok {
+		return ref
+	}
+	return normalizeDocument(value)
+}
+
+// normalizeExtensions normalizes the unknown fields of an object, which must
+// be specification extensions.
+func normalizeExtensions(object string, extensions map[string]interface{}) (map[string]interface{}, error) {
+	if extensions == nil {
+		return nil, nil
+	}
+	for _, key := range SortedKeys(extensions) {
+		if !strings.HasPrefix(key, "x-") {
+			return nil, fmt.Errorf("unknown field %q in %s", key, object)
+		}
+	}
+	return normalizeDocument(extensions).(map[string]interface{}), nil
+}
+
+func normalizeDocuments(documents map[string]interface{}) map[string]interface{} {
+	if documents == nil {
+		return nil
+	}
+	return normalizeDocument(documents).(map[string]interface{})
+}
+
+func (c *ComponentsObject) init() {
+	if c.Schemas == nil {
+		c.Schemas = map[string]*Schema{}
+	}
+	if c.Parameters == nil {
+		c.Parameters = map[string]*ParameterObject{}
+	}
+	if c.RequestBodies == nil {
+		c.RequestBodies = map[string]*RequestBodyObject{}
+	}
+	if c.Responses == nil {
+		c.Responses = map[string]*ResponseObject{}
+	}
+	if c.SecuritySchemes == nil {
+		c.SecuritySchemes = map[string]*SecuritySchemeObject{}
+	}
+	if c.Callbacks == nil {
+		c.Callbacks = map[string]*CallbackObject{}
+	}
+	if c.PathItems == nil {
+		c.PathItems = map[string]*PathItemObject{}
+	}
+}
+
+func (oapi *OpenAPIObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain OpenAPIObject
+	if err := unmarshal((*plain)(oapi)); err != nil {
+		return err
+	}
+	var err error
+	if oapi.Extensions, err = normalizeExtensions("OpenAPI object", oapi.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (info *InfoObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain InfoObject
+	if err := unmarshal((*plain)(info)); err != nil {
+		return err
+	}
+	var err error
+	if info.Extensions, err = normalizeExtensions("info object", info.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (license *LicenseObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain LicenseObject
+	if err := unmarshal((*plain)(license)); err != nil {
+		return err
+	}
+	var err error
+	if license.Extensions, err = normalizeExtensions("license object", license.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (contact *ContactObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ContactObject
+	if err := unmarshal((*plain)(contact)); err != nil {
+		return err
+	}
+	var err error
+	if contact.Extensions, err = normalizeExtensions("contact object", contact.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (docs *ExternalDocumentationObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ExternalDocumentationObject
+	if err := unmarshal((*plain)(docs)); err != nil {
+		return err
+	}
+	var err error
+	if docs.Extensions, err = normalizeExtensions("external documentation object", docs.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (server *ServerObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ServerObject
+	if err := unmarshal((*plain)(server)); err != nil {
+		return err
+	}
+	var err error
+	if server.Extensions, err = normalizeExtensions("server object", server.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tag *TagObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain TagObject
+	if err := unmarshal((*plain)(tag)); err != nil {
+		return err
+	}
+	var err error
+	if tag.Extensions, err = normalizeExtensions("tag object", tag.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (example *ExampleObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ExampleObject
+	if err := unmarshal((*plain)(example)); err != nil {
+		return err
+	}
+	example.Value = normalizeDocument(example.Value)
+	var err error
+	if example.Extensions, err = normalizeExtensions("example object", example.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (mediaType *MediaTypeObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain MediaTypeObject
+	if err := unmarshal((*plain)(mediaType)); err != nil {
+		return err
+	}
+	mediaType.Schema = decodeSchema(mediaType.Schema)
+	mediaType.Example = normalizeDocument(mediaType.Example)
+	mediaType.Encoding = normalizeDocuments(mediaType.Encoding)
+	var err error
+	if mediaType.Extensions, err = normalizeExtensions("media type object", mediaType.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (scheme *SecuritySchemeObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain SecuritySchemeObject
+	if err := unmarshal((*plain)(scheme)); err != nil {
+		return err
+	}
+	scheme.Flows = normalizeDocument(scheme.Flows)
+	var err error
+	if scheme.Extensions, err = normalizeExtensions("security scheme object", scheme.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (response *ResponseObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ResponseObject
+	if err := unmarshal((*plain)(response)); err != nil {
+		return err
+	}
+	response.Headers = normalizeDocuments(response.Headers)
+	response.Links = normalizeDocuments(response.Links)
+	var err error
+	if response.Extensions, err = normalizeExtensions("response object", response.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (requestBody *RequestBodyObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain RequestBodyObject
+	if err := unmarshal((*plain)(requestBody)); err != nil {
+		return err
+	}
+	var err error
+	if requestBody.Extensions, err = normalizeExtensions("request body object", requestBody.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (parameter *ParameterObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ParameterObject
+	if err := unmarshal((*plain)(parameter)); err != nil {
+		return err
+	}
+	parameter.Schema = decodeSchema(parameter.Schema)
+	parameter.Example = normalizeDocument(parameter.Example)
+	var err error
+	if parameter.Extensions, err = normalizeExtensions("parameter object", parameter.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (path *PathItemObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain PathItemObject
+	if err := unmarshal((*plain)(path)); err != nil {
+		return err
+	}
+	var err error
+	if path.Extensions, err = normalizeExtensions("path item object", path.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *ComponentsObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ComponentsObject
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	for id, schema := range c.Schemas {
+		if schema == nil {
+			continue
+		}
+		value := decodeSchema(*schema)
+		c.Schemas[id] = &value
+	}
+	c.Examples = normalizeDocuments(c.Examples)
+	c.Headers = normalizeDocuments(c.Headers)
+	c.Links = normalizeDocuments(c.Links)
+	var err error
+	if c.Extensions, err = normalizeExtensions("components object", c.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (op *OperationObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain OperationObject
+	if err := unmarshal((*plain)(op)); err != nil {
+		return err
+	}
+
+	for i, value := range op.Parameters {
+		if ref, ok := decodeReference(value); ok {
+			op.Parameters[i] = ref
+			continue
+		}
+		parameter := NewParameterObject()
+		if err := decodeValue(value, parameter); err != nil {
+			return err
+		}
+		op.Parameters[i] = parameter
+	}
+
+	if op.RequestBody != nil {
+		if ref, ok := decodeReference(op.RequestBody); ok {
+			op.RequestBody = ref
+		} else {
+			requestBody := NewRequestBodyObject()
+			if err := decodeValue(op.RequestBody, requestBody); err != nil {
+				return err
+			}
+			op.RequestBody = requestBody
+		}
+	}
+
+	if op.Responses == nil {
+		op.Responses = map[string]Response{}
+	}
+	for statusCode, value := range op.Responses {
+		if ref, ok := decodeReference(value); ok {
+			op.Responses[statusCode] = ref
+			continue
+		}
+		response := NewResponseObject()
+		if err := decodeValue(value, response); err != nil {
+			return err
+		}
+		op.Responses[statusCode] = response
+	}
+
+	if op.Callbacks == nil {
+		op.Callbacks = map[string]Callback{}
+	}
+	for key, value := range op.Callbacks {
+		if ref, ok := decodeReference(value); ok {
+			op.Callbacks[key] = ref
+			continue
+		}
+		callback := NewCallbackObject()
+		if err := decodeValue(value, callback); err != nil {
+			return err
+		}
+		op.Callbacks[key] = callback
+	}
+
+	var err error
+	if op.Extensions, err = normalizeExtensions("operation object", op.Extensions); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package openapi3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported OpenAPI specification versions.
+const (
+	Version30 = "3.0.0"
+	Version31 = "3.1.0"
+)
+
+// ParseVersion returns the supported OpenAPI specification version matching
+// the version, e.g. 3.1 or 3.1.0.
+func ParseVersion(version string) (string, error) {
+	switch {
+	case version == "3.0" || strings.HasPrefix(version, "3.0."):
+		return Version30, nil
+	case version == "3.1" || strings.HasPrefix(version, "3.1."):
+		return Version31, nil
+	default:
+		return "", fmt.Errorf("unsupported OpenAPI version: %v", version)
+	}
+}
+
+// IsVersion31 returns whether the version is an OpenAPI 3.1 version, which
+// uses the JSON Schema 2020-12 dialect for schemas.
+func IsVersion31(version string) bool {
+	return version == "3.1" || strings.HasPrefix(version, "3.1.")
+}
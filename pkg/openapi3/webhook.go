@@ -0,0 +1,17 @@
+package openapi3
+
+// WebhooksObject maps webhook names to path items of the requests sent by
+// the API.
+type WebhooksObject map[string]PathItemObject
+
+func NewWebhooksObject() *WebhooksObject {
+	return &WebhooksObject{}
+}
+
+func (webhooks *WebhooksObject) SetPath(name string, item PathItemObject) {
+	(*webhooks)[name] = item
+}
+
+func (webhooks *WebhooksObject) GetPath(name string) PathItemObject {
+	return (*webhooks)[name]
+}
@@ -0,0 +1,444 @@
+package overlay
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+)
+
+// node is a value selected in a document, with the keys and indices locating
+// it from the document root.
+type node struct {
+	path  []interface{}
+	value interface{}
+}
+
+func (n node) child(key interface{}, value interface{}) node {
+	path := make([]interface{}, len(n.path)+1)
+	copy(path, n.path)
+	path[len(n.path)] = key
+	return node{path: path, value: value}
+}
+
+// children returns the member values of an object, sorted by key, or the
+// elements of an array.
+func (n node) children() []node {
+	var nodes []node
+	switch v := n.value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			nodes = append(nodes, n.child(key, v[key]))
+		}
+	case []interface{}:
+		for i, item := range v {
+			nodes = append(nodes, n.child(i, item))
+		}
+	}
+	return nodes
+}
+
+// descendants returns the node and all its descendants, in document order.
+func (n node) descendants() []node {
+	nodes := []node{n}
+	for _, child := range n.children() {
+		nodes = append(nodes, child.descendants()...)
+	}
+	return nodes
+}
+
+// selector selects nodes from a node.
+type selector func(n node) []node
+
+// Path is a compiled JSONPath expression. A subset of JSONPath is supported:
+// child names ($.a, $['a']), indices ($[0]), wildcards ($.*, $[*]),
+// recursive descent ($..a) and filters comparing a relative path with a
+// literal ($[?(@.a == 'b')]) or testing its existence ($[?(@.a)]).
+type Path struct {
+	segments []segment
+}
+
+type segment struct {
+	recursive bool
+	selector  selector
+}
+
+func (p *Path) evaluate(root interface{}) []node {
+	nodes := []node{{value: root}}
+	for _, seg := range p.segments {
+		var next []node
+		for _, n := range nodes {
+			candidates := []node{n}
+			if seg.recursive {
+				candidates = n.descendants()
+			}
+			for _, candidate := range candidates {
+				next = append(next, seg.selector(candidate)...)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// Select returns the values selected by the path in the document.
+func (p *Path) Select(doc interface{}) []interface{} {
+	var values []interface{}
+	for _, n := range p.evaluate(doc) {
+		values = append(values, n.value)
+	}
+	return values
+}
+
+// CompilePath compiles a JSONPath expression.
+func CompilePath(expr string) (*Path, error) {
+	parser := &pathParser{expr: expr}
+	path, err := parser.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %v", expr, err)
+	}
+	return path, nil
+}
+
+type pathParser struct {
+	expr string
+	pos  int
+}
+
+func (p *pathParser) peek(prefix string) bool {
+	return strings.HasPrefix(p.expr[p.pos:], prefix)
+}
+
+func (p *pathParser) consume(prefix string) bool {
+	if p.peek(prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) skipSpaces() {
+	for p.pos < len(p.expr) && p.expr[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *pathParser) parse() (*Path, error) {
+	if !p.consume("$") {
+		return nil, fmt.Errorf("must start with $")
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.expr) {
+		return nil, fmt.Errorf("unexpected %q", p.expr[p.pos:])
+	}
+	return &Path{segments: segments}, nil
+}
+
+// parseSegments parses segments until the end of the expression or a token
+// not starting a segment.
+func (p *pathParser) parseSegments() ([]segment, error) {
+	var segments []segment
+	for p.pos < len(p.expr) {
+		var seg segment
+		switch {
+		case p.consume(".."):
+			seg.recursive = true
+			if p.peek("[") {
+				break
+			}
+			fallthrough
+		case p.consume("."):
+			if p.consume("*") {
+				seg.selector = selectWildcard
+			} else {
+				name := p.parseName()
+				if name == "" {
+					return nil, fmt.Errorf("expected name at %d", p.pos)
+				}
+				seg.selector = selectName(name)
+			}
+		case p.peek("["):
+		default:
+			return segments, nil
+		}
+
+		if seg.selector == nil {
+			selector, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			seg.selector = selector
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+func (p *pathParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.expr) && !strings.ContainsRune(".[ =!<>)&|", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	return p.expr[start:p.pos]
+}
+
+func (p *pathParser) parseBracket() (selector, error) {
+	if !p.consume("[") {
+		return nil, fmt.Errorf("expected [ at %d", p.pos)
+	}
+	p.skipSpaces()
+
+	var sel selector
+	switch {
+	case p.consume("*"):
+		sel = selectWildcard
+	case p.consume("?"):
+		p.skipSpaces()
+		parens := p.consume("(")
+		filter, err := p.parseFilter()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if parens && !p.consume(")") {
+			return nil, fmt.Errorf("expected ) at %d", p.pos)
+		}
+		sel = selectFilter(filter)
+	case p.peek("'") || p.peek(`"`):
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		sel = selectName(name)
+	default:
+		start := p.pos
+		p.consume("-")
+		for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+			p.pos++
+		}
+		index, err := strconv.Atoi(p.expr[start:p.pos])
+		if err != nil {
+			return nil, fmt.Errorf("expected index at %d", start)
+		}
+		sel = selectIndex(index)
+	}
+
+	p.skipSpaces()
+	if !p.consume("]") {
+		return nil, fmt.Errorf("expected ] at %d", p.pos)
+	}
+	return sel, nil
+}
+
+func (p *pathParser) parseString() (string, error) {
+	quote := p.expr[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.expr):
+			b.WriteByte(p.expr[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// filter tests a candidate node of a filter selector.
+type filter func(n node) bool
+
+func (p *pathParser) parseFilter() (filter, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		switch {
+		case p.consume("&&"):
+			right, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			l := left
+			left = func(n node) bool { return l(n) && right(n) }
+		case p.consume("||"):
+			right, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			l := left
+			left = func(n node) bool { return l(n) || right(n) }
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *pathParser) parseComparison() (filter, error) {
+	p.skipSpaces()
+	negate := p.consume("!")
+	p.skipSpaces()
+	if !p.consume("@") {
+		return nil, fmt.Errorf("expected @ at %d", p.pos)
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	relative := &Path{segments: segments}
+
+	p.skipSpaces()
+	var op string
+	for _, candidate := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	if op == "" {
+		return func(n node) bool {
+			return (len(relative.evaluate(n.value)) > 0) != negate
+		}, nil
+	}
+	if negate {
+		return nil, fmt.Errorf("unexpected ! in comparison")
+	}
+
+	p.skipSpaces()
+	literal, err := p.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+	return func(n node) bool {
+		for _, value := range relative.Select(n.value) {
+			if compare(value, op, literal) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (p *pathParser) parseLiteral() (interface{}, error) {
+	if p.peek("'") || p.peek(`"`) {
+		return p.parseString()
+	}
+	for _, keyword := range []struct {
+		token string
+		value interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if p.consume(keyword.token) {
+			return keyword.value, nil
+		}
+	}
+	start := p.pos
+	for p.pos < len(p.expr) && strings.ContainsRune("-+.0123456789eE", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected literal at %d", start)
+	}
+	return n, nil
+}
+
+func compare(value interface{}, op string, literal interface{}) bool {
+	switch op {
+	case "==":
+		return jsonschema.Equal(value, literal)
+	case "!=":
+		return !jsonschema.Equal(value, literal)
+	}
+
+	a, okA := number(value)
+	b, okB := number(literal)
+	if !okA || !okB {
+		return false
+	}
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+func selectName(name string) selector {
+	return func(n node) []node {
+		obj, ok := n.value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value, exists := obj[name]
+		if !exists {
+			return nil
+		}
+		return []node{n.child(name, value)}
+	}
+}
+
+func selectIndex(index int) selector {
+	return func(n node) []node {
+		arr, ok := n.value.([]interface{})
+		if !ok {
+			return nil
+		}
+		i := index
+		if i < 0 {
+			i += len(arr)
+		}
+		if i < 0 || i >= len(arr) {
+			return nil
+		}
+		return []node{n.child(i, arr[i])}
+	}
+}
+
+func selectWildcard(n node) []node {
+	return n.children()
+}
+
+func selectFilter(f filter) selector {
+	return func(n node) []node {
+		var nodes []node
+		for _, child := range n.children() {
+			if f(child) {
+				nodes = append(nodes, child)
+			}
+		}
+		return nodes
+	}
+}
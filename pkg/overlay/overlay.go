@@ -0,0 +1,229 @@
+package overlay
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"gopkg.in/yaml.v2"
+)
+
+// Overlay is an OpenAPI Overlay document, describing changes to apply to
+// OpenAPI documents.
+type Overlay struct {
+	Version string      `yaml:"overlay"`
+	Info    OverlayInfo `yaml:"info"`
+	Extends string      `yaml:"extends,omitempty"`
+	Actions []Action    `yaml:"actions"`
+}
+
+type OverlayInfo struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// Action updates or removes the values selected by its JSONPath target.
+type Action struct {
+	Target      string      `yaml:"target"`
+	Description string      `yaml:"description,omitempty"`
+	Update      interface{} `yaml:"update,omitempty"`
+	Remove      bool        `yaml:"remove,omitempty"`
+
+	path *Path
+}
+
+// Load parses an overlay document in YAML or JSON.
+func Load(data []byte) (*Overlay, error) {
+	var overlay Overlay
+	if err := yaml.Unmarshal(data, &overlay); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(overlay.Version, "1.") {
+		return nil, fmt.Errorf("unsupported overlay version: %q", overlay.Version)
+	}
+	if len(overlay.Actions) == 0 {
+		return nil, fmt.Errorf("overlay must have at least one action")
+	}
+	for i := range overlay.Actions {
+		action := &overlay.Actions[i]
+		path, err := CompilePath(action.Target)
+		if err != nil {
+			return nil, errors.Wrapf(err, "action %d", i)
+		}
+		action.path = path
+		action.Update = openapi3.NormalizeValue(action.Update)
+	}
+	return &overlay, nil
+}
+
+func LoadFile(path string) (*Overlay, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	overlay, err := Load(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load overlay %s", path)
+	}
+	return overlay, nil
+}
+
+// Apply applies the overlay to the OpenAPI document, returning the updated
+// document.
+func (o *Overlay) Apply(oapi *openapi3.OpenAPIObject) (*openapi3.OpenAPIObject, error) {
+	doc, err := openapi3.ToDocument(oapi)
+	if err != nil {
+		return nil, err
+	}
+	doc, err = o.ApplyDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.Load(data)
+}
+
+// ApplyDocument applies the overlay to a generic document, returning the
+// updated document. Actions are applied in order; actions with targets
+// selecting no values have no effect.
+//
+// Objects selected by update actions are merged with the update object
+// recursively, replacing values other than objects; the update value is
+// appended to arrays selected.
+func (o *Overlay) ApplyDocument(doc interface{}) (interface{}, error) {
+	root := &doc
+	for i, action := range o.Actions {
+		nodes := action.path.evaluate(*root)
+		if action.Remove {
+			// Remove later array elements first, to keep the indices of
+			// earlier elements valid.
+			sort.SliceStable(nodes, func(i, j int) bool {
+				return comparePaths(nodes[i].path, nodes[j].path) > 0
+			})
+			for _, n := range nodes {
+				if len(n.path) == 0 {
+					return nil, fmt.Errorf("action %d: cannot remove document root", i)
+				}
+				remove(root, n.path)
+			}
+			continue
+		}
+
+		if action.Update == nil {
+			continue
+		}
+		for _, n := range nodes {
+			switch target := n.value.(type) {
+			case map[string]interface{}:
+				update, ok := action.Update.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("action %d: update of object must be an object", i)
+				}
+				mergeObject(target, update)
+			case []interface{}:
+				set(root, n.path, append(target, copyValue(action.Update)))
+			default:
+				return nil, fmt.Errorf("action %d: target must be an object or array", i)
+			}
+		}
+	}
+	return *root, nil
+}
+
+// mergeObject merges the update object into the target object recursively.
+func mergeObject(target map[string]interface{}, update map[string]interface{}) {
+	for key, value := range update {
+		targetObj, isTargetObj := target[key].(map[string]interface{})
+		updateObj, isUpdateObj := value.(map[string]interface{})
+		if isTargetObj && isUpdateObj {
+			mergeObject(targetObj, updateObj)
+		} else {
+			target[key] = copyValue(value)
+		}
+	}
+}
+
+func copyValue(value interface{}) interface{} {
+	return openapi3.NormalizeValue(value)
+}
+
+// parent returns the container of the value at the path.
+func parent(root *interface{}, path []interface{}) (interface{}, bool) {
+	value := *root
+	for _, key := range path[:len(path)-1] {
+		switch container := value.(type) {
+		case map[string]interface{}:
+			value = container[key.(string)]
+		case []interface{}:
+			if key.(int) >= len(container) {
+				return nil, false
+			}
+			value = container[key.(int)]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func set(root *interface{}, path []interface{}, value interface{}) {
+	if len(path) == 0 {
+		*root = value
+		return
+	}
+	container, ok := parent(root, path)
+	if !ok {
+		return
+	}
+	switch container := container.(type) {
+	case map[string]interface{}:
+		container[path[len(path)-1].(string)] = value
+	case []interface{}:
+		container[path[len(path)-1].(int)] = value
+	}
+}
+
+func remove(root *interface{}, path []interface{}) {
+	container, ok := parent(root, path)
+	if !ok {
+		return
+	}
+	switch container := container.(type) {
+	case map[string]interface{}:
+		delete(container, path[len(path)-1].(string))
+	case []interface{}:
+		i := path[len(path)-1].(int)
+		if i >= len(container) {
+			return
+		}
+		set(root, path[:len(path)-1], append(container[:i:i], container[i+1:]...))
+	}
+}
+
+// comparePaths compares paths in document order.
+func comparePaths(a, b []interface{}) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch ka := a[i].(type) {
+		case int:
+			if kb, ok := b[i].(int); ok && ka != kb {
+				if ka < kb {
+					return -1
+				}
+				return 1
+			}
+		case string:
+			if kb, ok := b[i].(string); ok && ka != kb {
+				return strings.Compare(ka, kb)
+			}
+		}
+	}
+	return len(a) - len(b)
+}
@@ -0,0 +1,143 @@
+package overlay
+
+import (
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+tags:
+- {name: users}
+- {name: internal}
+- {name: admin}
+paths:
+  /users:
+    get:
+      tags: [users]
+      responses:
+        "200": {description: OK}
+    post:
+      tags: [users]
+      x-internal: true
+      responses:
+        "201": {description: Created}
+  /admin:
+    get:
+      tags: [admin]
+      x-internal: true
+      responses:
+        "200": {description: OK}
+`
+
+func TestCompilePath(t *testing.T) {
+	Convey("CompilePath", t, func() {
+		doc, err := openapi3.ReadDocument([]byte(testDocument))
+		So(err, ShouldBeNil)
+
+		selectPath := func(expr string) []interface{} {
+			path, err := CompilePath(expr)
+			So(err, ShouldBeNil)
+			return path.Select(doc)
+		}
+
+		Convey("should select child values", func() {
+			So(selectPath("$.info.title"), ShouldResemble, []interface{}{"Test API"})
+			So(selectPath("$['paths']['/users'].get.tags[0]"), ShouldResemble, []interface{}{"users"})
+			So(selectPath("$.tags[-1].name"), ShouldResemble, []interface{}{"admin"})
+			So(selectPath("$.info.license"), ShouldBeEmpty)
+		})
+
+		Convey("should select with wildcards and recursive descent", func() {
+			So(selectPath("$.tags[*].name"), ShouldResemble, []interface{}{"users", "internal", "admin"})
+			So(selectPath("$.paths.*.*.responses.*.description"), ShouldResemble, []interface{}{"OK", "OK", "Created"})
+			So(selectPath("$..x-internal"), ShouldResemble, []interface{}{true, true})
+		})
+
+		Convey("should select with filters", func() {
+			So(selectPath("$.tags[?(@.name == 'internal')].name"), ShouldResemble, []interface{}{"internal"})
+			So(selectPath("$.tags[?(@.name != 'internal' && @.name != 'admin')].name"), ShouldResemble, []interface{}{"users"})
+			So(selectPath("$.paths['/users'][?(@.x-internal)].responses.*.description"), ShouldResemble, []interface{}{"Created"})
+			So(selectPath("$.paths['/users'][?(!@.x-internal)].responses.*.description"), ShouldResemble, []interface{}{"OK"})
+		})
+
+		Convey("should reject invalid expressions", func() {
+			for _, expr := range []string{"info", "$.", "$[0", "$[?(@.a ==)]", "$['a"} {
+				_, err := CompilePath(expr)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestOverlay(t *testing.T) {
+	Convey("Overlay", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+
+		Convey("should update and remove values", func() {
+			o, err := Load([]byte(`
+overlay: 1.0.0
+info: {title: Public API, version: 1.0.0}
+actions:
+- target: $.paths.*[?(@.x-internal == true)]
+  remove: true
+- target: $.tags[?(@.name == 'internal' || @.name == 'admin')]
+  remove: true
+- target: $.info
+  update:
+    description: Public API.
+    x-logo: {url: logo.png}
+- target: $.tags
+  update: {name: public}
+- target: $.paths.*.*.responses
+  update:
+    default: {description: Error}
+`))
+			So(err, ShouldBeNil)
+
+			result, err := o.Apply(oapi)
+			So(err, ShouldBeNil)
+			doc, err := openapi3.ToDocument(result)
+			So(err, ShouldBeNil)
+
+			expected, err := openapi3.ReadDocument([]byte(`
+openapi: 3.0.0
+info:
+  title: Test API
+  description: Public API.
+  version: 1.0.0
+  x-logo: {url: logo.png}
+tags:
+- {name: users}
+- {name: public}
+paths:
+  /users:
+    get:
+      tags: [users]
+      responses:
+        "200": {description: OK}
+        default: {description: Error}
+  /admin: {}
+`))
+			So(err, ShouldBeNil)
+			So(doc, ShouldResemble, expected)
+		})
+
+		Convey("should reject invalid overlays", func() {
+			_, err := Load([]byte(`{overlay: 2.0.0, actions: [{target: $.info, remove: true}]}`))
+			So(err, ShouldBeError, `unsupported overlay version: "2.0.0"`)
+
+			_, err = Load([]byte(`{overlay: 1.0.0, actions: [{target: info, remove: true}]}`))
+			So(err, ShouldBeError, `action 0: invalid JSONPath "info": must start with $`)
+
+			o, err := Load([]byte(`{overlay: 1.0.0, actions: [{target: $.info.title, update: x}]}`))
+			So(err, ShouldBeNil)
+			_, err = o.Apply(oapi)
+			So(err, ShouldBeError, "action 0: target must be an object or array")
+		})
+	})
+}
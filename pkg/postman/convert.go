@@ -0,0 +1,387 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// Warning reports a construct of the OpenAPI document that cannot be
+// represented in the collection, and is dropped.
+type Warning struct {
+	// Pointer is the JSON pointer of the construct in the OpenAPI document.
+	Pointer string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Pointer, w.Message)
+}
+
+// baseURLVariable is the collection variable of the server URL.
+const baseURLVariable = "baseUrl"
+
+var templateVariable = regexp.MustCompile(`\{([^{}]+)\}`)
+
+type converter struct {
+	oapi       *openapi3.OpenAPIObject
+	collection *Collection
+	warnings   []Warning
+
+	// schemes records the security schemes used, whose credentials are
+	// collection variables.
+	schemes map[string]bool
+}
+
+func (c *converter) warn(pointer string, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, Warning{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// Convert converts the OpenAPI document to a Postman collection, with a
+// folder of requests of each tag. Requests are pre-filled from schema
+// defaults and examples, and server variables and credentials of security
+// schemes are collection variables.
+func Convert(oapi *openapi3.OpenAPIObject) (*Collection, []Warning) {
+	c := &converter{
+		oapi: oapi,
+		collection: &Collection{
+			Info: Info{
+				Name:        oapi.Info.Title,
+				Description: oapi.Info.Description,
+				Schema:      Schema,
+			},
+			Item: []*Item{},
+		},
+		schemes: map[string]bool{},
+	}
+
+	c.convertServers()
+	c.collection.Auth = c.convertSecurity("/security", oapi.Security)
+
+	folders := map[string]*Item{}
+	folder := func(name string) *Item {
+		if f, ok := folders[name]; ok {
+			return f
+		}
+		f := &Item{Name: name}
+		folders[name] = f
+		c.collection.Item = append(c.collection.Item, f)
+		return f
+	}
+	for _, tag := range oapi.Tags {
+		folder(tag.Name).Description = tag.Description
+	}
+
+	var untagged []*Item
+	for _, path := range openapi3.SortedKeys(oapi.Paths) {
+		item := oapi.Paths[path]
+		for _, method := range openapi3.Methods {
+			op := item.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			request := c.convertOperation(path, method, op)
+			// Operations with multiple tags are in the folder of the first tag.
+			if len(op.Tags) == 0 {
+				untagged = append(untagged, request)
+			} else {
+				f := folder(op.Tags[0])
+				f.Item = append(f.Item, request)
+			}
+		}
+	}
+
+	// Declared tags without operations are omitted.
+	var items []*Item
+	for _, item := range c.collection.Item {
+		if len(item.Item) > 0 {
+			items = append(items, item)
+		}
+	}
+	c.collection.Item = append(items, untagged...)
+
+	for _, name := range openapi3.SortedKeys(c.schemes) {
+		c.collection.Variable = append(c.collection.Variable, c.credentialVariables(name)...)
+	}
+	return c.collection, c.warnings
+}
+
+// convertServers converts the first server to the base URL variable, and its
+// variables to collection variables.
+func (c *converter) convertServers() {
+	if len(c.oapi.Servers) == 0 {
+		c.collection.Variable = append(c.collection.Variable, Variable{Key: baseURLVariable})
+		return
+	}
+	if len(c.oapi.Servers) > 1 {
+		c.warn("/servers", "only the first server is used")
+	}
+
+	server := c.oapi.Servers[0]
+	url := templateVariable.ReplaceAllString(strings.TrimSuffix(server.URL, "/"), "{{$1}}")
+	c.collection.Variable = append(c.collection.Variable, Variable{
+		Key:         baseURLVariable,
+		Value:       url,
+		Description: server.Description,
+	})
+	for _, name := range openapi3.SortedKeys(server.Variables) {
+		variable := server.Variables[name]
+		description := variable.Description
+		if len(variable.Enum) > 0 {
+			if description != "" {
+				description += " "
+			}
+			description += "(" + strings.Join(variable.Enum, ", ") + ")"
+		}
+		c.collection.Variable = append(c.collection.Variable, Variable{
+			Key:         name,
+			Value:       variable.Default,
+			Description: description,
+		})
+	}
+}
+
+func (c *converter) convertOperation(path string, method string, op *openapi3.OperationObject) *Item {
+	pointer := openapi3.Pointer("paths", path, strings.ToLower(method))
+	name := op.Summary
+	if name == "" {
+		name = method + " " + path
+	}
+	request := &Request{
+		Method:      method,
+		Description: op.Description,
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = templateVariable.ReplaceAllString(segment, ":$1")
+	}
+	request.URL = URL{
+		Raw:  "{{" + baseURLVariable + "}}/" + strings.Join(segments, "/"),
+		Host: []string{"{{" + baseURLVariable + "}}"},
+		Path: segments,
+	}
+
+	var params []*openapi3.ParameterObject
+	item := c.oapi.Paths[path]
+	for i := range item.Parameters {
+		params = append(params, &item.Parameters[i])
+	}
+	for _, param := range op.Parameters {
+		if paramObj := c.oapi.ResolveParameter(param); paramObj != nil {
+			params = append(params, paramObj)
+		}
+	}
+	var query []string
+	var cookies []string
+	for _, param := range params {
+		value := c.parameterValue(param)
+		switch param.Location {
+		case openapi3.ParameterLocationPath:
+			request.URL.Variable = append(request.URL.Variable, Variable{
+				Key:         param.Name,
+				Value:       value,
+				Description: param.Description,
+			})
+		case openapi3.ParameterLocationQuery:
+			disabled := !param.Required && value == ""
+			request.URL.Query = append(request.URL.Query, QueryParam{
+				Key:         param.Name,
+				Value:       value,
+				Description: param.Description,
+				Disabled:    disabled,
+			})
+			if !disabled {
+				query = append(query, param.Name+"="+value)
+			}
+		case openapi3.ParameterLocationHeader:
+			request.Header = append(request.Header, Header{
+				Key:         param.Name,
+				Value:       value,
+				Description: param.Description,
+				Disabled:    !param.Required && value == "",
+			})
+		case openapi3.ParameterLocationCookie:
+			cookies = append(cookies, param.Name+"="+value)
+		}
+	}
+	if len(cookies) > 0 {
+		request.Header = append(request.Header, Header{Key: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+	if len(query) > 0 {
+		request.URL.Raw += "?" + strings.Join(query, "&")
+	}
+
+	if body := c.oapi.ResolveRequestBody(op.RequestBody); body != nil {
+		c.convertBody(request, body)
+	}
+
+	if op.Servers != nil {
+		c.warn(pointer+"/servers", "servers of operations are not supported")
+	}
+	if op.Security != nil {
+		request.Auth = c.convertSecurity(pointer+"/security", op.Security)
+	}
+
+	return &Item{Name: name, Request: request}
+}
+
+// parameterValue returns the schema default or the first example of the
+// parameter.
+func (c *converter) parameterValue(param *openapi3.ParameterObject) string {
+	schema, _ := c.oapi.ResolveSchema(param.Schema)
+	if value, ok := schema["default"]; ok {
+		return formatValue(value)
+	}
+	if param.Example != nil {
+		return formatValue(param.Example)
+	}
+	if names := openapi3.SortedKeys(param.Examples); len(names) > 0 {
+		return formatValue(param.Examples[names[0]].Value)
+	}
+	if value, ok := schema["example"]; ok {
+		return formatValue(value)
+	}
+	return ""
+}
+
+// formatValue formats a parameter value; items of arrays are separated by
+// commas.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			items = append(items, formatValue(item))
+		}
+		return strings.Join(items, ",")
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// convertBody converts the JSON media type, or the first media type, of the
+// request body. JSON bodies are pre-filled from the first example.
+func (c *converter) convertBody(request *Request, body *openapi3.RequestBodyObject) {
+	mediaTypes := openapi3.SortedKeys(body.Content)
+	if len(mediaTypes) == 0 {
+		return
+	}
+	mediaType := mediaTypes[0]
+	if _, ok := body.Content["application/json"]; ok {
+		mediaType = "application/json"
+	}
+	request.Header = append(request.Header, Header{Key: "Content-Type", Value: mediaType})
+
+	request.Body = &Body{Mode: "raw"}
+	if !strings.HasSuffix(mediaType, "json") {
+		return
+	}
+	request.Body.Options = &BodyOptions{Raw: BodyRawOptions{Language: "json"}}
+
+	mt := body.Content[mediaType]
+	value := mt.Example
+	if names := openapi3.SortedKeys(mt.Examples); len(names) > 0 {
+		value = mt.Examples[names[0]].Value
+	}
+	if value != nil {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err == nil {
+			request.Body.Raw = string(data)
+		}
+	}
+}
+
+// convertSecurity converts the first alternative of the security
+// requirements. Requirements without alternatives, or an empty requirement,
+// disable authentication.
+func (c *converter) convertSecurity(pointer string, security openapi3.SecurityRequirements) *Auth {
+	if security == nil {
+		return nil
+	}
+	if len(security) == 0 || len(security[0]) == 0 {
+		return &Auth{Type: "noauth"}
+	}
+	if len(security) > 1 {
+		c.warn(pointer, "only the first alternative of security requirements is used")
+	}
+	names := openapi3.SortedKeys(security[0])
+	if len(names) > 1 {
+		c.warn(pointer+"/0", "only security scheme %s is used", names[0])
+	}
+
+	name := names[0]
+	scheme, ok := c.oapi.Components.SecuritySchemes[name]
+	if !ok {
+		c.warn(pointer+"/0", "security scheme %s is not defined", name)
+		return nil
+	}
+	auth := c.convertSecurityScheme(name, scheme)
+	if auth != nil {
+		c.schemes[name] = true
+	}
+	return auth
+}
+
+func (c *converter) convertSecurityScheme(name string, scheme *openapi3.SecuritySchemeObject) *Auth {
+	pointer := openapi3.Pointer("components", "securitySchemes", name)
+	switch scheme.Type {
+	case openapi3.SecuritySchemeTypeAPIKey:
+		if scheme.APIKeyLocation == openapi3.SecuritySchemeAPIKeyLocationCookie {
+			c.warn(pointer, "API keys in cookies are not supported")
+			return nil
+		}
+		return &Auth{Type: "apikey", APIKey: []AuthAttribute{
+			{Key: "key", Value: scheme.APIKeyName, Type: "string"},
+			{Key: "value", Value: "{{" + name + "}}", Type: "string"},
+			{Key: "in", Value: string(scheme.APIKeyLocation), Type: "string"},
+		}}
+	case openapi3.SecuritySchemeTypeHTTP:
+		switch strings.ToLower(scheme.HTTPAuthScheme) {
+		case "basic":
+			return &Auth{Type: "basic", Basic: []AuthAttribute{
+				{Key: "username", Value: "{{" + name + "Username}}", Type: "string"},
+				{Key: "password", Value: "{{" + name + "Password}}", Type: "string"},
+			}}
+		case "bearer":
+			return &Auth{Type: "bearer", Bearer: []AuthAttribute{
+				{Key: "token", Value: "{{" + name + "}}", Type: "string"},
+			}}
+		default:
+			c.warn(pointer, "HTTP authentication scheme %s is not supported", scheme.HTTPAuthScheme)
+			return nil
+		}
+	case "oauth2", "openIdConnect":
+		return &Auth{Type: "oauth2", OAuth2: []AuthAttribute{
+			{Key: "accessToken", Value: "{{" + name + "}}", Type: "string"},
+			{Key: "addTokenTo", Value: "header", Type: "string"},
+		}}
+	default:
+		c.warn(pointer, "security scheme type %s is not supported", scheme.Type)
+		return nil
+	}
+}
+
+// credentialVariables returns the collection variables of the credentials of
+// the security scheme.
+func (c *converter) credentialVariables(name string) []Variable {
+	scheme := c.oapi.Components.SecuritySchemes[name]
+	description := scheme.Description
+	if scheme.Type == openapi3.SecuritySchemeTypeHTTP && strings.ToLower(scheme.HTTPAuthScheme) == "basic" {
+		return []Variable{
+			{Key: name + "Username", Description: description},
+			{Key: name + "Password", Description: description},
+		}
+	}
+	return []Variable{{Key: name, Description: description}}
+}
@@ -0,0 +1,94 @@
+package postman
+
+// Schema is the schema URL of converted collections.
+const Schema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection is a Postman Collection v2.1.
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []*Item    `json:"item"`
+	Auth     *Auth      `json:"auth,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// Item is a folder if it has items, or a request otherwise.
+type Item struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Item        []*Item  `json:"item,omitempty"`
+	Request     *Request `json:"request,omitempty"`
+}
+
+type Request struct {
+	Method      string   `json:"method"`
+	Description string   `json:"description,omitempty"`
+	URL         URL      `json:"url"`
+	Header      []Header `json:"header,omitempty"`
+	Body        *Body    `json:"body,omitempty"`
+	Auth        *Auth    `json:"auth,omitempty"`
+}
+
+type URL struct {
+	Raw      string       `json:"raw"`
+	Host     []string     `json:"host"`
+	Path     []string     `json:"path,omitempty"`
+	Query    []QueryParam `json:"query,omitempty"`
+	Variable []Variable   `json:"variable,omitempty"`
+}
+
+type QueryParam struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type Header struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type Body struct {
+	Mode    string       `json:"mode"`
+	Raw     string       `json:"raw"`
+	Options *BodyOptions `json:"options,omitempty"`
+}
+
+type BodyOptions struct {
+	Raw BodyRawOptions `json:"raw"`
+}
+
+type BodyRawOptions struct {
+	Language string `json:"language"`
+}
+
+// Variable is a collection or path variable.
+type Variable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// Auth is the authentication of a collection or request. Attributes of the
+// type, e.g. "apikey", are specified in the field of the type.
+type Auth struct {
+	Type   string          `json:"type"`
+	APIKey []AuthAttribute `json:"apikey,omitempty"`
+	Bearer []AuthAttribute `json:"bearer,omitempty"`
+	Basic  []AuthAttribute `json:"basic,omitempty"`
+	OAuth2 []AuthAttribute `json:"oauth2,omitempty"`
+}
+
+type AuthAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
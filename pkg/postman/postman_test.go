@@ -0,0 +1,129 @@
+package postman
+
+import (
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+servers:
+- url: 'https://{env}.example.com/v1/'
+  variables:
+    env: {default: api, enum: [api, staging]}
+- url: 'https://example.com/'
+security:
+- access_token: []
+tags:
+- {name: User, description: User APIs}
+paths:
+  /users:
+    get:
+      tags: [User]
+      summary: List users
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, default: 20}}
+      - {name: cursor, in: query, schema: {type: string}}
+      - {name: X-Request-ID, in: header, required: true, example: abc}
+      - {name: session, in: cookie, schema: {type: string}, examples: {a: {value: s1}, b: {value: s2}}}
+      responses:
+        "200": {description: OK}
+    post:
+      tags: [User, Admin]
+      requestBody:
+        $ref: '#/components/requestBodies/User'
+      security:
+      - basic: []
+      responses:
+        "201": {description: Created}
+  /users/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: string}, example: me}
+    delete:
+      security: []
+      responses:
+        "204": {description: Deleted}
+components:
+  requestBodies:
+    User:
+      content:
+        application/json:
+          schema: {type: object}
+          examples:
+            Test: {value: {name: Test}}
+  securitySchemes:
+    access_token: {type: http, scheme: bearer, description: Access token}
+    basic: {type: http, scheme: basic}
+`
+
+func TestConvert(t *testing.T) {
+	Convey("Convert", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+		collection, warnings := Convert(oapi)
+
+		So(warnings, ShouldResemble, []Warning{
+			{Pointer: "/servers", Message: "only the first server is used"},
+		})
+		So(collection.Info, ShouldResemble, Info{Name: "Test API", Schema: Schema})
+		So(collection.Variable, ShouldResemble, []Variable{
+			{Key: "baseUrl", Value: "https://{{env}}.example.com/v1"},
+			{Key: "env", Value: "api", Description: "(api, staging)"},
+			{Key: "access_token", Description: "Access token"},
+			{Key: "basicUsername"},
+			{Key: "basicPassword"},
+		})
+		So(collection.Auth, ShouldResemble, &Auth{Type: "bearer", Bearer: []AuthAttribute{
+			{Key: "token", Value: "{{access_token}}", Type: "string"},
+		}})
+
+		So(collection.Item, ShouldHaveLength, 2)
+		folder := collection.Item[0]
+		So(folder.Name, ShouldEqual, "User")
+		So(folder.Description, ShouldEqual, "User APIs")
+		So(folder.Item, ShouldHaveLength, 2)
+
+		Convey("should pre-fill parameters", func() {
+			list := folder.Item[0]
+			So(list.Name, ShouldEqual, "List users")
+			So(list.Request.URL, ShouldResemble, URL{
+				Raw:  "{{baseUrl}}/users?limit=20",
+				Host: []string{"{{baseUrl}}"},
+				Path: []string{"users"},
+				Query: []QueryParam{
+					{Key: "limit", Value: "20"},
+					{Key: "cursor", Disabled: true},
+				},
+			})
+			So(list.Request.Header, ShouldResemble, []Header{
+				{Key: "X-Request-ID", Value: "abc"},
+				{Key: "Cookie", Value: "session=s1"},
+			})
+			So(list.Request.Auth, ShouldBeNil)
+		})
+
+		Convey("should pre-fill body from example", func() {
+			create := folder.Item[1]
+			So(create.Name, ShouldEqual, "POST /users")
+			So(create.Request.Header, ShouldResemble, []Header{{Key: "Content-Type", Value: "application/json"}})
+			So(create.Request.Body, ShouldResemble, &Body{
+				Mode:    "raw",
+				Raw:     "{\n  \"name\": \"Test\"\n}",
+				Options: &BodyOptions{Raw: BodyRawOptions{Language: "json"}},
+			})
+			So(create.Request.Auth.Type, ShouldEqual, "basic")
+		})
+
+		Convey("should convert path variables", func() {
+			remove := collection.Item[1]
+			So(remove.Name, ShouldEqual, "DELETE /users/{id}")
+			So(remove.Request.URL.Raw, ShouldEqual, "{{baseUrl}}/users/:id")
+			So(remove.Request.URL.Path, ShouldResemble, []string{"users", ":id"})
+			So(remove.Request.URL.Variable, ShouldResemble, []Variable{{Key: "id", Value: "me"}})
+			So(remove.Request.Auth, ShouldResemble, &Auth{Type: "noauth"})
+		})
+	})
+}
@@ -115,6 +115,39 @@ const (
 	// @Callback UserCreated
 	AnnotationTypeCallback
 
+	// @Summary <Summary>
+	// e.g.
+	// @Summary API for testing
+	AnnotationTypeSummary
+
+	// @License <Name> [<SPDX Identifier>|<URL>]
+	// (identifiers are supported in OpenAPI 3.1 only)
+	// e.g.
+	// @License Apache-2.0 Apache-2.0
+	// @License Proprietary https://example.com/license
+	AnnotationTypeLicense
+
+	// @Webhook <Name>
+	// [<Description>]
+	// e.g.
+	// @Webhook user.created
+	//     Sent when a user is created.
+	//     @Operation POST - User created
+	AnnotationTypeWebhook
+
+	// @Discriminator <Property>
+	// [<Description>]
+	// e.g.
+	// @Discriminator type
+	//     Event payload
+	AnnotationTypeDiscriminator
+
+	// @DiscriminatorValue <Value>|<Constant>
+	// e.g.
+	// @DiscriminatorValue user.created
+	// @DiscriminatorValue EventTypeUserCreated
+	AnnotationTypeDiscriminatorValue
+
 	AnnotationTypeMaximum
 )
 
@@ -165,10 +198,16 @@ func trimEmptyLines(body []string) []string {
 }
 
 func ParseAnnotations(lines []string) []Annotation {
-	var annotations []Annotation
+	annotations, _ := parseAnnotations(lines)
+	return annotations
+}
+
+// parseAnnotations returns the annotations in the lines, and the indexes of
+// the lines of the annotations.
+func parseAnnotations(lines []string) (annotations []Annotation, indexes []int) {
 	var current *Annotation
 
-	for _, line := range lines {
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		annotation, ok := tryParseAnnotation(line)
 		if ok {
@@ -177,6 +216,7 @@ func ParseAnnotations(lines []string) []Annotation {
 				annotations = append(annotations, *current)
 			}
 			current = &annotation
+			indexes = append(indexes, i)
 		} else if current != nil {
 			current.Body = append(current.Body, line)
 		}
@@ -185,5 +225,5 @@ func ParseAnnotations(lines []string) []Annotation {
 		current.Body = trimEmptyLines(current.Body)
 		annotations = append(annotations, *current)
 	}
-	return annotations
+	return
 }
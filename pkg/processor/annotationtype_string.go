@@ -24,12 +24,17 @@ func _() {
 	_ = x[AnnotationTypeJSONSchema-13]
 	_ = x[AnnotationTypeJSONExample-14]
 	_ = x[AnnotationTypeCallback-15]
-	_ = x[AnnotationTypeMaximum-16]
+	_ = x[AnnotationTypeSummary-16]
+	_ = x[AnnotationTypeLicense-17]
+	_ = x[AnnotationTypeWebhook-18]
+	_ = x[AnnotationTypeDiscriminator-19]
+	_ = x[AnnotationTypeDiscriminatorValue-20]
+	_ = x[AnnotationTypeMaximum-21]
 }
 
-const _AnnotationType_name = "IDAPIVersionServerVariableTagSecurityRequirementSecuritySchemeAPIKeySecuritySchemeHTTPOperationParameterRequestBodyResponseJSONSchemaJSONExampleCallbackMaximum"
+const _AnnotationType_name = "IDAPIVersionServerVariableTagSecurityRequirementSecuritySchemeAPIKeySecuritySchemeHTTPOperationParameterRequestBodyResponseJSONSchemaJSONExampleCallbackSummaryLicenseWebhookDiscriminatorDiscriminatorValueMaximum"
 
-var _AnnotationType_index = [...]uint8{0, 2, 5, 12, 18, 26, 29, 48, 68, 86, 95, 104, 115, 123, 133, 144, 152, 159}
+var _AnnotationType_index = [...]uint8{0, 2, 5, 12, 18, 26, 29, 48, 68, 86, 95, 104, 115, 123, 133, 144, 152, 159, 166, 173, 186, 204, 211}
 
 func (i AnnotationType) String() string {
 	if i < 0 || i >= AnnotationType(len(_AnnotationType_index)-1) {
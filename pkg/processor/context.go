@@ -3,6 +3,8 @@ package processor
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
+	"strconv"
 	"strings"
 
 	"github.com/skygeario/openapi3-gen/pkg/openapi3"
@@ -12,6 +14,36 @@ type context struct {
 	astNodeName  string
 	astNodeValue string
 	componentID  string
+	position     token.Position
+	ignore       []string
+
+	// annotationPosition is the position of the annotation being consumed.
+	annotationPosition token.Position
+
+	operations         []OperationDeclaration
+	schemas            []SchemaDeclaration
+	discriminators     []DiscriminatorDeclaration
+	discriminatorValue string
+	sourceMap          SourceMap
+
+	// JSON pointers of the operation and callback objects in context.
+	operationPointer string
+	callbackPointer  string
+	// objectPointer is the JSON pointer of the parameter, request body or
+	// response object in context.
+	objectPointer string
+
+	examples []exampleDeclaration
+
+	// webhook is the name of the webhook in context, and webhookDescription
+	// is its description.
+	webhook            string
+	webhookDescription string
+	// webhookPosition is the position of the webhook annotation in context,
+	// until an operation of the webhook is declared.
+	webhookPosition *token.Position
+	// errs are errors found outside of the annotation being consumed.
+	errs []error
 
 	oapi        *openapi3.OpenAPIObject
 	server      *openapi3.ServerObject
@@ -22,13 +54,16 @@ type context struct {
 	callback    *openapi3.CallbackObject
 }
 
-func newContext(oapi *openapi3.OpenAPIObject, node ast.Node) *context {
+func newContext(oapi *openapi3.OpenAPIObject, node ast.Node, position token.Position, ignore []string, sourceMap SourceMap) *context {
 	name, _ := extractDeclName(node)
 	value, _ := extractConstValue(node)
 	return &context{
 		astNodeName:  name,
 		astNodeValue: value,
 		componentID:  name,
+		position:     position,
+		ignore:       ignore,
+		sourceMap:    sourceMap,
 		oapi:         oapi,
 	}
 }
@@ -61,6 +96,44 @@ func (ctx *context) setContextObject(scope interface{}) {
 	}
 }
 
+// webhookPointer returns the JSON pointer of the webhook in context. Webhooks
+// are emitted as the x-webhooks extension in OpenAPI 3.0.
+func (ctx *context) webhookPointer() string {
+	if openapi3.IsVersion31(ctx.oapi.Version) {
+		return openapi3.Pointer("webhooks", ctx.webhook)
+	}
+	return openapi3.Pointer(webhooksExtension, ctx.webhook)
+}
+
+// endWebhook ends the webhook in context, reporting it if it has no
+// operations.
+func (ctx *context) endWebhook() {
+	if ctx.webhookPosition != nil {
+		ctx.errs = append(ctx.errs, processorError{
+			inner:    fmt.Errorf("must be followed by Operation"),
+			position: *ctx.webhookPosition,
+		})
+	}
+	ctx.webhook = ""
+	ctx.webhookDescription = ""
+	ctx.webhookPosition = nil
+}
+
+// locate records the declaration as the source of the object at the JSON
+// pointer.
+func (ctx *context) locate(pointer string) {
+	ctx.sourceMap[pointer] = Source{Position: ctx.annotationPosition, Ignore: ctx.ignore}
+}
+
+// locateParameter locates the last parameter of the operation, returning its
+// JSON pointer.
+func (ctx *context) locateParameter() string {
+	index := strconv.Itoa(len(ctx.operation.Parameters) - 1)
+	pointer := ctx.operationPointer + openapi3.Pointer("parameters", index)
+	ctx.locate(pointer)
+	return pointer
+}
+
 func (ctx *context) Consume(annotation Annotation) error {
 	handler, exists := handlers[annotation.Type]
 	if !exists {
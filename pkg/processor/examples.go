@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// exampleDeclaration records a JSON example annotation, and the JSON
+// pointers of the example and the schema it is validated against.
+type exampleDeclaration struct {
+	name     string
+	pointer  string
+	schema   string
+	position token.Position
+}
+
+// validateExamples validates the values of JSON examples against the schemas
+// of their parameters or media types.
+func (psr *Processor) validateExamples() []error {
+	if len(psr.examples) == 0 {
+		return nil
+	}
+	doc, err := openapi3.ToDocument(psr.oapi)
+	if err != nil {
+		return []error{err}
+	}
+	compiler := jsonschema.NewOpenAPICompiler(doc)
+
+	var errs []error
+	for _, example := range psr.examples {
+		if _, err := jsonschema.ResolvePointer(doc, example.schema); err != nil {
+			// Examples without schemas are not validated.
+			continue
+		}
+		value, err := jsonschema.ResolvePointer(doc, example.pointer+"/value")
+		if err != nil {
+			continue
+		}
+		schema, err := compiler.Compile("#" + example.schema)
+		if err != nil {
+			errs = append(errs, processorError{inner: err, position: example.position})
+			continue
+		}
+		for _, violation := range schema.Validate(value) {
+			errs = append(errs, processorError{
+				inner:    fmt.Errorf("example %s does not match schema: %v", example.name, violation),
+				position: example.position,
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"gopkg.in/yaml.v2"
+)
+
+// fileArgFormat is the format of files of annotation bodies, e.g.
+// "file:schemas/user.json".
+var fileArgFormat = regexp.MustCompile(`^file:(\S+)$`)
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// loadFile loads the JSON, or YAML if the file has a .yaml or .yml
+// extension, at the path relative to the directory of the annotated file.
+// Parse errors are located in the file.
+func (ctx *context) loadFile(path string) (interface{}, error) {
+	file := filepath.Join(filepath.Dir(ctx.annotationPosition.Filename), filepath.FromSlash(path))
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			if matches := yamlErrorLine.FindStringSubmatch(err.Error()); matches != nil {
+				return nil, fmt.Errorf("%s:%s: %s", file, matches[1], strings.TrimPrefix(err.Error(), matches[0]))
+			}
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		// Values are decoded as JSON, so that numbers are float64 as in
+		// JSON files and annotation bodies.
+		data, err := json.Marshal(openapi3.NormalizeValue(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		value = nil
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		return value, nil
+	default:
+		if err := json.Unmarshal(data, &value); err != nil {
+			var offset int64
+			switch err := err.(type) {
+			case *json.SyntaxError:
+				offset = err.Offset
+			case *json.UnmarshalTypeError:
+				offset = err.Offset
+			default:
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+			line := bytes.Count(data[:offset], []byte("\n")) + 1
+			column := int(offset) - bytes.LastIndexByte(data[:offset], '\n') - 1
+			return nil, fmt.Errorf("%s:%d:%d: %v", file, line, column, err)
+		}
+		return value, nil
+	}
+}
+
+// parseBody parses the JSON of the annotation body, or loads the file of the
+// annotation argument.
+func (ctx *context) parseBody(file string, body string) (interface{}, error) {
+	if file == "" {
+		var value interface{}
+		err := json.Unmarshal([]byte(body), &value)
+		return value, err
+	}
+	if strings.TrimSpace(body) != "" {
+		return nil, fmt.Errorf("cannot declare both file and body")
+	}
+	return ctx.loadFile(file)
+}
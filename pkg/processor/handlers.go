@@ -1,9 +1,9 @@
 package processor
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -18,6 +18,9 @@ var refArgFormat = regexp.MustCompile(`^{([^\s]+)}$`)
 // e.g. GET /me - Get current user
 var operationArgFormat = regexp.MustCompile(`^([^\s]+)\s+([^\s]+)\s+-\s+(.+)$`)
 
+// e.g. POST - User created
+var webhookOperationArgFormat = regexp.MustCompile(`^([^\s]+)\s+-\s+(.+)$`)
+
 // e.g. DisableUserExpiring - Disable a user with expiry
 var exampleArgFormat = regexp.MustCompile(`^([^\s]+)\s+-\s+(.+)$`)
 
@@ -29,12 +32,103 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 	AnnotationTypeAPI: func(ctx *context, arg string, body string) error {
 		ctx.oapi.Info.Title = arg
 		ctx.oapi.Info.Description = body
+		ctx.locate(openapi3.Pointer("info"))
 		return nil
 	},
 	AnnotationTypeVersion: func(ctx *context, arg string, body string) error {
 		ctx.oapi.Info.Version = arg
 		return nil
 	},
+	AnnotationTypeSummary: func(ctx *context, arg string, body string) error {
+		if ctx.operation != nil {
+			return fmt.Errorf("must not be used with Operation")
+		}
+		if openapi3.IsVersion31(ctx.oapi.Version) {
+			ctx.oapi.Info.Summary = arg
+		} else {
+			setExtension(&ctx.oapi.Info.Extensions, "x-summary", arg)
+		}
+		return nil
+	},
+	AnnotationTypeLicense: func(ctx *context, arg string, body string) error {
+		fields := strings.Fields(arg)
+		if len(fields) < 1 || len(fields) > 2 {
+			return fmt.Errorf("must provide license name and optional identifier or URL")
+		}
+
+		license := &openapi3.LicenseObject{Name: fields[0]}
+		if len(fields) == 2 {
+			if strings.Contains(fields[1], "://") {
+				license.URL = fields[1]
+			} else if openapi3.IsVersion31(ctx.oapi.Version) {
+				license.Identifier = fields[1]
+			} else {
+				return fmt.Errorf("license identifier requires OpenAPI 3.1, provide license URL instead")
+			}
+		}
+		ctx.oapi.Info.License = license
+		return nil
+	},
+	AnnotationTypeWebhook: func(ctx *context, arg string, body string) error {
+		if ctx.operation != nil && ctx.webhook == "" {
+			return fmt.Errorf("must not be used with Operation")
+		}
+		fields := strings.Fields(arg)
+		if len(fields) != 1 {
+			return fmt.Errorf("must provide webhook name")
+		}
+
+		// The webhook is registered with its first operation.
+		ctx.endWebhook()
+		position := ctx.annotationPosition
+		ctx.webhook = fields[0]
+		ctx.webhookDescription = body
+		ctx.webhookPosition = &position
+		ctx.operation = nil
+		ctx.parameter = nil
+		ctx.requestBody = nil
+		ctx.response = nil
+		ctx.callback = nil
+		ctx.locate(ctx.webhookPointer())
+		return nil
+	},
+	AnnotationTypeDiscriminator: func(ctx *context, arg string, body string) error {
+		fields := strings.Fields(arg)
+		if len(fields) != 1 {
+			return fmt.Errorf("must provide discriminator property name")
+		}
+		if ctx.componentID == "" {
+			return fmt.Errorf("must provide component ID")
+		}
+
+		// oneOf and mapping are resolved from implementing types.
+		schemaValue := map[string]interface{}{
+			"discriminator": map[string]interface{}{"propertyName": fields[0]},
+		}
+		if body != "" {
+			schemaValue["description"] = body
+		}
+		var schema openapi3.Schema = schemaValue
+		id := ctx.componentID
+		ctx.oapi.Components.Schemas[id] = &schema
+		ctx.locate(openapi3.Pointer("components", "schemas", id))
+		ctx.discriminators = append(ctx.discriminators, DiscriminatorDeclaration{
+			Name:     ctx.astNodeName,
+			ID:       id,
+			Property: fields[0],
+			Position: ctx.position,
+		})
+		ctx.componentID = ""
+		return nil
+	},
+	AnnotationTypeDiscriminatorValue: func(ctx *context, arg string, body string) error {
+		fields := strings.Fields(arg)
+		if len(fields) != 1 {
+			return fmt.Errorf("must provide discriminator value")
+		}
+		ctx.discriminatorValue = fields[0]
+		return nil
+	},
 	AnnotationTypeServer: func(ctx *context, arg string, body string) error {
 		server := openapi3.NewServerObject()
 		server.URL = arg
@@ -43,6 +137,7 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 		servers := append(ctx.oapi.Servers, *server)
 		ctx.oapi.Servers = servers
 		ctx.setContextObject(&servers[len(servers)-1])
+		ctx.locate(openapi3.Pointer("servers", strconv.Itoa(len(servers)-1)))
 		return nil
 	},
 	AnnotationTypeVariable: func(ctx *context, arg string, body string) error {
@@ -75,6 +170,7 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 				Description: body,
 			}
 			ctx.oapi.Tags = append(ctx.oapi.Tags, tag)
+			ctx.locate(openapi3.Pointer("tags", strconv.Itoa(len(ctx.oapi.Tags)-1)))
 		}
 		return nil
 	},
@@ -114,6 +210,7 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			Description:    body,
 		}
 		ctx.oapi.Components.SecuritySchemes[name] = scheme
+		ctx.locate(openapi3.Pointer("components", "securitySchemes", name))
 		return nil
 	},
 	AnnotationTypeSecuritySchemeHTTP: func(ctx *context, arg string, body string) error {
@@ -139,30 +236,47 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			Description:      body,
 		}
 		ctx.oapi.Components.SecuritySchemes[name] = scheme
+		ctx.locate(openapi3.Pointer("components", "securitySchemes", name))
 		return nil
 	},
 	AnnotationTypeOperation: func(ctx *context, arg string, body string) error {
-		matches, success := matchRegex(arg, operationArgFormat)
-		if !success {
+		var method, path, summary string
+		if matches, success := matchRegex(arg, operationArgFormat); success {
+			method, path, summary = matches[0], matches[1], matches[2]
+			if ctx.webhook != "" && ctx.callback == nil {
+				if !strings.HasPrefix(path, "/") {
+					ctx.webhookPosition = nil
+					return fmt.Errorf("path must be omitted in Webhook")
+				}
+				// Operations with paths end the webhook.
+				ctx.endWebhook()
+			}
+		} else if matches, success := matchRegex(arg, webhookOperationArgFormat); success && ctx.webhook != "" {
+			method, path, summary = matches[0], ctx.webhook, matches[1]
+		} else {
 			return fmt.Errorf("must provide HTTP method and path")
 		}
 
-		method := matches[0]
-		path := matches[1]
-		var summary string
-		if len(matches) == 3 {
-			summary = matches[2]
-		}
-
 		operation := openapi3.NewOperationObject()
 		operation.Summary = summary
 		operation.Description = body
 
 		var paths openapi3.Paths
+		var pointer, pathPointer string
 		if ctx.callback != nil {
 			paths = ctx.callback
+			pointer = ctx.callbackPointer + openapi3.Pointer(path, strings.ToLower(method))
+		} else if ctx.webhook != "" {
+			ctx.webhookPosition = nil
+			if ctx.oapi.Webhooks == nil {
+				ctx.oapi.Webhooks = openapi3.WebhooksObject{}
+			}
+			paths = &ctx.oapi.Webhooks
+			pointer = ctx.webhookPointer() + openapi3.Pointer(strings.ToLower(method))
 		} else {
 			paths = &ctx.oapi.Paths
+			pathPointer = openapi3.Pointer("paths", path)
+			pointer = pathPointer + openapi3.Pointer(strings.ToLower(method))
 		}
 
 		pathItem := paths.GetPath(path)
@@ -170,8 +284,27 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			return fmt.Errorf("invalid HTTP method: %v", method)
 		}
 
+		if ctx.webhook != "" && ctx.callback == nil && ctx.webhookDescription != "" {
+			pathItem.Description = ctx.webhookDescription
+		}
 		paths.SetPath(path, pathItem)
 		ctx.setContextObject(operation)
+		ctx.operationPointer = pointer
+		ctx.locate(pointer)
+		// Path items are located at their first operation.
+		if _, ok := ctx.sourceMap[pathPointer]; pathPointer != "" && !ok {
+			ctx.locate(pathPointer)
+		}
+
+		if ctx.callback == nil && ctx.webhook == "" {
+			ctx.operations = append(ctx.operations, OperationDeclaration{
+				Name:      ctx.astNodeName,
+				Method:    method,
+				Path:      path,
+				Position:  ctx.position,
+				Operation: operation,
+			})
+		}
 
 		return nil
 	},
@@ -183,6 +316,7 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			}
 			id := matches[0]
 			ctx.operation.Parameters = append(ctx.operation.Parameters, openapi3.MakeParameterRef(id))
+			ctx.locateParameter()
 			return nil
 		}
 
@@ -203,17 +337,22 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 		parameter.Required = location == openapi3.ParameterLocationPath
 		parameter.Description = body
 
+		var pointer string
 		if ctx.operation != nil {
 			ctx.operation.Parameters = append(ctx.operation.Parameters, parameter)
+			pointer = ctx.locateParameter()
 		} else {
 			if ctx.componentID == "" {
 				return fmt.Errorf("must provide component ID")
 			}
 			ctx.oapi.Components.Parameters[ctx.componentID] = parameter
+			pointer = openapi3.Pointer("components", "parameters", ctx.componentID)
+			ctx.locate(pointer)
 			ctx.componentID = ""
 		}
 
 		ctx.setContextObject(parameter)
+		ctx.objectPointer = pointer
 
 		return nil
 	},
@@ -225,22 +364,28 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			}
 			id := matches[0]
 			ctx.operation.RequestBody = openapi3.MakeRequestBodyRef(id)
+			ctx.locate(ctx.operationPointer + openapi3.Pointer("requestBody"))
 			return nil
 		}
 
 		requestBody := openapi3.NewRequestBodyObject()
 		requestBody.Description = body
+		var pointer string
 		if ctx.operation != nil {
 			ctx.operation.RequestBody = requestBody
+			pointer = ctx.operationPointer + openapi3.Pointer("requestBody")
 		} else {
 			if ctx.componentID == "" {
 				return fmt.Errorf("must provide component ID")
 			}
 			ctx.oapi.Components.RequestBodies[ctx.componentID] = requestBody
+			pointer = openapi3.Pointer("components", "requestBodies", ctx.componentID)
 			ctx.componentID = ""
 		}
+		ctx.locate(pointer)
 
 		ctx.setContextObject(requestBody)
+		ctx.objectPointer = pointer
 
 		return nil
 	},
@@ -253,9 +398,12 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 				return fmt.Errorf("must provide component ID")
 			}
 			ctx.oapi.Components.Responses[ctx.componentID] = response
+			pointer := openapi3.Pointer("components", "responses", ctx.componentID)
+			ctx.locate(pointer)
 			ctx.componentID = ""
 
 			ctx.setContextObject(response)
+			ctx.objectPointer = pointer
 		} else {
 			var response openapi3.Response
 			var statusCode string
@@ -280,6 +428,11 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			}
 
 			ctx.operation.Responses[statusCode] = response
+			pointer := ctx.operationPointer + openapi3.Pointer("responses", statusCode)
+			ctx.locate(pointer)
+			if _, isObject := response.(*openapi3.ResponseObject); isObject {
+				ctx.objectPointer = pointer
+			}
 		}
 
 		return nil
@@ -293,23 +446,31 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			id = matches[1]
 			schema = openapi3.MakeSchemaRef(id)
 		} else {
+			var file string
+			if matches, ok := matchRegex(arg, fileArgFormat); ok {
+				file = matches[0]
+			}
+
 			schemaValue := body
-			if len(schemaValue) == 0 {
+			if len(schemaValue) == 0 && file == "" {
 				schemaValue = ctx.astNodeValue
 			}
 
-			valid := len(schemaValue) > 0
+			valid := len(schemaValue) > 0 || file != ""
 			if !valid {
 				return fmt.Errorf("invalid json schema declaration")
 			}
 
-			var jsonSchema map[string]interface{}
-			err := json.Unmarshal([]byte(schemaValue), &jsonSchema)
+			value, err := ctx.parseBody(file, schemaValue)
 			if err != nil {
 				return errors.Wrap(err, "invalid json schema")
 			}
+			jsonSchema, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("invalid json schema: must be an object")
+			}
 
-			jsonSchema = translateJSONSchema(jsonSchema).(map[string]interface{})
+			jsonSchema = openapi3.ConvertSchema(translateJSONSchema(jsonSchema), ctx.oapi.Version).(map[string]interface{})
 
 			id, _ = jsonSchema["$id"].(string)
 			if len(id) > 0 {
@@ -347,18 +508,17 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 				return fmt.Errorf("schema must contains non-empty top-level '$id' property")
 			}
 			ctx.oapi.Components.Schemas[id] = &schema
+			ctx.locate(openapi3.Pointer("components", "schemas", id))
+			ctx.schemas = append(ctx.schemas, SchemaDeclaration{
+				Name:     ctx.astNodeName,
+				ID:       id,
+				Position: ctx.position,
+			})
 		}
 
 		return nil
 	},
 	AnnotationTypeJSONExample: func(ctx *context, arg string, body string) error {
-		var value interface{}
-
-		err := json.Unmarshal([]byte(body), &value)
-		if err != nil {
-			return errors.Wrap(err, "invalid json example")
-		}
-
 		matches, success := matchRegex(arg, exampleArgFormat)
 		if !success {
 			return fmt.Errorf("must provide example name and summary")
@@ -366,12 +526,36 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 		name := matches[0]
 		summary := matches[1]
 
+		// The file may follow the summary, or be given without summary.
+		var file string
+		if fields := strings.Fields(summary); len(fields) > 0 {
+			if fileMatches, ok := matchRegex(fields[len(fields)-1], fileArgFormat); ok {
+				file = fileMatches[0]
+				summary = strings.Join(fields[:len(fields)-1], " ")
+			}
+		}
+
+		value, err := ctx.parseBody(file, body)
+		if err != nil {
+			return errors.Wrap(err, "invalid json example")
+		}
+
 		example := openapi3.ExampleObject{
 			Summary: summary,
 			Value:   value,
 		}
+		// Schemas may be annotated after examples; examples are validated
+		// after processing.
+		declaration := exampleDeclaration{
+			name:     name,
+			pointer:  ctx.objectPointer + openapi3.Pointer("content", jsonMediaType, "examples", name),
+			schema:   ctx.objectPointer + openapi3.Pointer("content", jsonMediaType, "schema"),
+			position: ctx.annotationPosition,
+		}
 		if ctx.parameter != nil {
 			ctx.parameter.Examples[name] = example
+			declaration.pointer = ctx.objectPointer + openapi3.Pointer("examples", name)
+			declaration.schema = ctx.objectPointer + openapi3.Pointer("schema")
 		} else if ctx.requestBody != nil {
 			mediaType, exists := ctx.requestBody.Content[jsonMediaType]
 			if !exists {
@@ -389,6 +573,7 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 		} else {
 			return fmt.Errorf("invalid annotation usage")
 		}
+		ctx.examples = append(ctx.examples, declaration)
 
 		return nil
 	},
@@ -400,6 +585,8 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 
 			callback := openapi3.NewCallbackObject()
 			ctx.oapi.Components.Callbacks[ctx.componentID] = callback
+			ctx.callbackPointer = openapi3.Pointer("components", "callbacks", ctx.componentID)
+			ctx.locate(ctx.callbackPointer)
 			ctx.componentID = ""
 			ctx.setContextObject(callback)
 		} else {
@@ -410,6 +597,7 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			case 1:
 				callback = openapi3.NewCallbackObject()
 				callbackKey = fields[0]
+				ctx.callbackPointer = ctx.operationPointer + openapi3.Pointer("callbacks", callbackKey)
 				ctx.setContextObject(callback)
 			case 2:
 				callbackKey = fields[0]
@@ -424,6 +612,7 @@ var handlers map[AnnotationType]annotationHandler = map[AnnotationType]annotatio
 			}
 
 			ctx.operation.Callbacks[callbackKey] = callback
+			ctx.locate(ctx.operationPointer + openapi3.Pointer("callbacks", callbackKey))
 		}
 
 		return nil
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
-	"strings"
 
 	"github.com/skygeario/openapi3-gen/pkg/openapi3"
 )
@@ -22,21 +21,97 @@ func (err processorError) Error() string {
 	return fmt.Sprintf("%v: %v", err.position, err.inner)
 }
 
+// OperationDeclaration records the Go declaration an operation annotation
+// is attached to.
+type OperationDeclaration struct {
+	Name      string
+	Method    string
+	Path      string
+	Position  token.Position
+	Operation *openapi3.OperationObject
+}
+
+// SchemaDeclaration records the Go declaration a component schema is
+// attached to.
+type SchemaDeclaration struct {
+	Name     string
+	ID       string
+	Position token.Position
+	// DiscriminatorValue is the discriminator value of the schema in
+	// polymorphic schemas, or a constant name, if specified.
+	DiscriminatorValue string
+}
+
+// DiscriminatorDeclaration records the Go interface type declaration of a
+// polymorphic component schema.
+type DiscriminatorDeclaration struct {
+	Name     string
+	ID       string
+	Property string
+	Position token.Position
+}
+
 type Processor struct {
-	oapi *openapi3.OpenAPIObject
-	errs []error
+	oapi           *openapi3.OpenAPIObject
+	errs           []error
+	operations     []OperationDeclaration
+	schemas        []SchemaDeclaration
+	discriminators []DiscriminatorDeclaration
+	examples       []exampleDeclaration
+	sourceMap      SourceMap
 }
 
 func New() *Processor {
+	return NewForVersion(openapi3.Version30)
+}
+
+// NewForVersion returns a processor generating documents of the OpenAPI
+// version; annotations are translated to the constructs of the version.
+func NewForVersion(version string) *Processor {
+	oapi := openapi3.NewOpenAPIObject()
+	oapi.Version = version
 	return &Processor{
-		oapi: openapi3.NewOpenAPIObject(),
+		oapi:      oapi,
+		sourceMap: SourceMap{},
 	}
 }
 
+// webhooksExtension is the extension emitting webhooks in OpenAPI 3.0.
+const webhooksExtension = "x-webhooks"
+
 func (psr *Processor) End() (*openapi3.OpenAPIObject, []error) {
+	if len(psr.oapi.Webhooks) > 0 && !openapi3.IsVersion31(psr.oapi.Version) {
+		setExtension(&psr.oapi.Extensions, webhooksExtension, psr.oapi.Webhooks)
+		psr.oapi.Webhooks = nil
+	}
+	psr.errs = append(psr.errs, psr.validateExamples()...)
 	return psr.oapi, psr.errs
 }
 
+// Operations returns the declarations of operations in paths, in the order
+// they are processed. Operations declared inside callbacks are not included.
+func (psr *Processor) Operations() []OperationDeclaration {
+	return psr.operations
+}
+
+// Schemas returns the declarations of component schemas, in the order they
+// are processed.
+func (psr *Processor) Schemas() []SchemaDeclaration {
+	return psr.schemas
+}
+
+// Discriminators returns the declarations of polymorphic component schemas,
+// in the order they are processed.
+func (psr *Processor) Discriminators() []DiscriminatorDeclaration {
+	return psr.discriminators
+}
+
+// SourceMap returns the source positions of objects in the generated
+// document.
+func (psr *Processor) SourceMap() SourceMap {
+	return psr.sourceMap
+}
+
 func (psr *Processor) Process(fset *token.FileSet, file *ast.File) {
 	ast.Inspect(file, func(n ast.Node) bool {
 		if decl, ok := n.(*ast.FuncDecl); ok {
@@ -49,23 +124,27 @@ func (psr *Processor) Process(fset *token.FileSet, file *ast.File) {
 }
 
 func (psr *Processor) processNode(fset *token.FileSet, node ast.Node, doc *ast.CommentGroup) {
-	docLines := strings.Split(doc.Text(), "\n")
-	annotations := ParseAnnotations(docLines)
+	position := fset.Position(node.Pos())
+	lines, positions := commentLines(fset, doc, position)
+	docLines, docPositions, ignore := parseIgnoreDirectives(lines, positions)
+	annotations, indexes := parseAnnotations(docLines)
 
-	errs := psr.processAnnotations(node, annotations)
-	for _, err := range errs {
-		err = processorError{inner: err, position: fset.Position(node.Pos())}
-		psr.errs = append(psr.errs, err)
-	}
-}
-
-func (psr *Processor) processAnnotations(node ast.Node, annotations []Annotation) (errs []error) {
-	ctx := newContext(psr.oapi, node)
-	for _, annotation := range annotations {
+	ctx := newContext(psr.oapi, node, position, ignore, psr.sourceMap)
+	for i, annotation := range annotations {
+		ctx.annotationPosition = docPositions[indexes[i]]
 		err := ctx.Consume(annotation)
 		if err != nil {
-			errs = append(errs, err)
+			err = processorError{inner: err, position: ctx.annotationPosition}
+			psr.errs = append(psr.errs, err)
 		}
 	}
-	return
+	ctx.endWebhook()
+	psr.errs = append(psr.errs, ctx.errs...)
+	psr.operations = append(psr.operations, ctx.operations...)
+	for _, schema := range ctx.schemas {
+		schema.DiscriminatorValue = ctx.discriminatorValue
+		psr.schemas = append(psr.schemas, schema)
+	}
+	psr.discriminators = append(psr.discriminators, ctx.discriminators...)
+	psr.examples = append(psr.examples, ctx.examples...)
 }
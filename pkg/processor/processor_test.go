@@ -3,6 +3,9 @@ package processor
 import (
 	"go/parser"
 	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/skygeario/openapi3-gen/pkg/openapi3"
@@ -10,8 +13,8 @@ import (
 )
 
 func TestProcessor(t *testing.T) {
-	process := func(sources ...string) (*openapi3.OpenAPIObject, []error) {
-		psr := New()
+	processVersion := func(version string, sources ...string) (*openapi3.OpenAPIObject, []error) {
+		psr := NewForVersion(version)
 		fset := token.NewFileSet()
 		for _, src := range sources {
 			file, _ := parser.ParseFile(fset, "", src, parser.ParseComments)
@@ -19,6 +22,9 @@ func TestProcessor(t *testing.T) {
 		}
 		return psr.oapi, psr.errs
 	}
+	process := func(sources ...string) (*openapi3.OpenAPIObject, []error) {
+		return processVersion(openapi3.Version30, sources...)
+	}
 
 	Convey("Processor", t, func() {
 		Convey("should process top-level annotations", func() {
@@ -88,7 +94,7 @@ func TestProcessor(t *testing.T) {
 					HTTPBearerFormat: "JWT",
 				},
 			})
-			So(oapi.Security, ShouldResemble, []openapi3.SecurityRequirementObject{
+			So(oapi.Security, ShouldResemble, openapi3.SecurityRequirements{
 				openapi3.SecurityRequirementObject{"api_key": []string{}},
 				openapi3.SecurityRequirementObject{"access_token": []string{}},
 			})
@@ -265,6 +271,140 @@ func TestProcessor(t *testing.T) {
 			})
 		})
 
+		Convey("should translate constructs to OpenAPI version", func() {
+			src := `
+				package main
+
+				/*
+					@API Test API
+					@Summary API for testing
+					@License MIT MIT
+				*/
+				func main() {}
+
+				// @JSONSchema
+				const User = ` + "`" + `
+				{
+					"$id": "#User",
+					"type": "object",
+					"properties": {
+						"name": { "type": "string", "nullable": true, "example": "Test" },
+						"age": { "type": "integer", "minimum": 0, "exclusiveMinimum": true },
+						"kind": { "const": "user" }
+					}
+				}
+				` + "`" + `
+			`
+
+			oapi, errs := process(src)
+			So(errs, ShouldHaveLength, 1)
+			So(errs[0].Error(), ShouldEndWith, "license identifier requires OpenAPI 3.1, provide license URL instead")
+			So(oapi.Version, ShouldEqual, "3.0.0")
+			So(oapi.Info.Summary, ShouldBeEmpty)
+			So(oapi.Info.Extensions, ShouldResemble, map[string]interface{}{"x-summary": "API for testing"})
+			So(oapi.Info.License, ShouldBeNil)
+			So(*oapi.Components.Schemas["User"], ShouldResemble, map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{"type": "string", "nullable": true, "example": "Test"},
+					"age":  map[string]interface{}{"type": "integer", "minimum": float64(0), "exclusiveMinimum": true},
+					"kind": map[string]interface{}{"enum": []interface{}{"user"}},
+				},
+			})
+
+			oapi, errs = processVersion(openapi3.Version31, src)
+			So(errs, ShouldBeEmpty)
+			So(oapi.Version, ShouldEqual, "3.1.0")
+			So(oapi.Info.Summary, ShouldEqual, "API for testing")
+			So(oapi.Info.Extensions, ShouldBeNil)
+			So(oapi.Info.License, ShouldResemble, &openapi3.LicenseObject{Name: "MIT", Identifier: "MIT"})
+			So(*oapi.Components.Schemas["User"], ShouldResemble, map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{"type": []interface{}{"string", "null"}, "examples": []interface{}{"Test"}},
+					"age":  map[string]interface{}{"type": "integer", "exclusiveMinimum": float64(0)},
+					"kind": map[string]interface{}{"const": "user"},
+				},
+			})
+		})
+
+		Convey("should process webhook annotations", func() {
+			src := `
+				package main
+
+				/*
+					@Webhook user.created
+						Sent when a user is created.
+						@Operation POST - User created
+							@RequestBody {User}
+							@Response 200
+								Event is received.
+				*/
+				func UserCreated() {}
+
+				/*
+					@Webhook payment.failed
+						@Operation POST - Payment failed
+							@Response 200
+								Event is received.
+					@Webhook payment.succeeded
+						Sent when a payment succeeds.
+						@Operation POST payment.succeeded - Payment succeeded
+					@Webhook payment.refunded
+					@Operation GET /payments - List payments
+						@Response 200
+							Payments.
+				*/
+				func PaymentEvents() {}
+			`
+
+			response := openapi3.NewResponseObject()
+			response.Description = "Event is received."
+			userCreated := openapi3.NewOperationObject()
+			userCreated.Summary = "User created"
+			userCreated.RequestBody = openapi3.MakeRequestBodyRef("User")
+			userCreated.Responses["200"] = response
+			paymentFailed := openapi3.NewOperationObject()
+			paymentFailed.Summary = "Payment failed"
+			paymentFailed.Responses["200"] = response
+			webhooks := openapi3.WebhooksObject{
+				"user.created": openapi3.PathItemObject{
+					Description: "Sent when a user is created.",
+					Post:        userCreated,
+				},
+				"payment.failed": openapi3.PathItemObject{Post: paymentFailed},
+			}
+			listPayments := openapi3.NewOperationObject()
+			listPayments.Summary = "List payments"
+			listPayments.Responses["200"] = &openapi3.ResponseObject{Description: "Payments.", Content: map[string]openapi3.MediaTypeObject{}}
+
+			psr := NewForVersion(openapi3.Version31)
+			fset := token.NewFileSet()
+			file, _ := parser.ParseFile(fset, "webhooks.go", src, parser.ParseComments)
+			psr.Process(fset, file)
+			oapi, errs := psr.End()
+			So(errs, ShouldHaveLength, 2)
+			So(errs[0].Error(), ShouldEqual, "webhooks.go:21:7: path must be omitted in Webhook")
+			So(errs[1].Error(), ShouldEqual, "webhooks.go:22:6: must be followed by Operation")
+			So(oapi.Webhooks, ShouldResemble, webhooks)
+			So(oapi.Extensions, ShouldBeNil)
+			So(oapi.Paths, ShouldResemble, openapi3.PathsObject{
+				"/payments": openapi3.PathItemObject{Get: listPayments},
+			})
+			So(psr.Operations(), ShouldHaveLength, 1)
+			So(psr.Operations()[0].Path, ShouldEqual, "/payments")
+			source, _ := psr.SourceMap().Lookup("/webhooks/user.created/post/responses/200")
+			So(source.Position.Line, ShouldEqual, 9)
+
+			psr = New()
+			psr.Process(fset, file)
+			oapi, _ = psr.End()
+			So(oapi.Webhooks, ShouldBeNil)
+			So(oapi.Extensions, ShouldResemble, map[string]interface{}{"x-webhooks": webhooks})
+			source, _ = psr.SourceMap().Lookup("/x-webhooks/payment.failed/post")
+			So(source.Position.Line, ShouldEqual, 16)
+		})
+
 		Convey("should process operation annotations", func() {
 			Convey("using reference objects", func() {
 				oapi, errs := process(`
@@ -311,7 +451,7 @@ func TestProcessor(t *testing.T) {
 				postOp.Summary = "Create User"
 				postOp.Description = "Create new user with specified information."
 				postOp.Tags = []string{"User Object"}
-				postOp.Security = []openapi3.SecurityRequirementObject{
+				postOp.Security = openapi3.SecurityRequirements{
 					openapi3.SecurityRequirementObject{"admin_key": []string{}},
 				}
 				postOp.RequestBody = openapi3.ReferenceObject{
@@ -438,3 +578,235 @@ func TestProcessor(t *testing.T) {
 		})
 	})
 }
+
+func TestExampleValidation(t *testing.T) {
+	Convey("Example validation", t, func() {
+		psr := New()
+		fset := token.NewFileSet()
+		file, _ := parser.ParseFile(fset, "api.go", `package main
+
+/*
+	@Operation POST /users - Create user
+		@Parameter limit query
+			@JSONExample Test - Test limit
+				"10"
+			@JSONSchema
+				{ "type": "integer" }
+		@RequestBody
+			@JSONExample Test - Test user
+				{ "name": 1 }
+			@JSONExample Valid - Valid user
+				{ "name": "Test", "nickname": null }
+			@JSONSchema {User}
+		@Response 200
+			OK
+			@JSONExample Untyped - Example without schema
+				{ "name": 1 }
+*/
+func CreateUser() {}
+
+// @JSONSchema
+const UserSchema = `+"`"+`
+{
+	"$id": "#User",
+	"type": "object",
+	"properties": {
+		"name": { "type": "string" },
+		"nickname": { "type": "string", "nullable": true }
+	}
+}
+`+"`"+`
+`, parser.ParseComments)
+		psr.Process(fset, file)
+		_, errs := psr.End()
+
+		var messages []string
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		So(messages, ShouldResemble, []string{
+			"api.go:6:4: example Test does not match schema: /: must be integer, got string",
+			"api.go:11:4: example Test does not match schema: /name: must be string, got integer",
+		})
+	})
+}
+
+func TestSourceMap(t *testing.T) {
+	Convey("SourceMap", t, func() {
+		psr := New()
+		fset := token.NewFileSet()
+		file, _ := parser.ParseFile(fset, "api.go", `package main
+
+/*
+	@API Test API
+	@Version 1.0.0
+*/
+func main() {}
+
+//openapi3-gen:ignore operation-tags operation-4xx-response
+/*
+	@Operation PATCH /user/{id} - Update user
+		@Callback user_updated
+			@Operation POST /user_updated - User is updated
+				@Response 200
+					Acknowledge the event.
+*/
+func updateUser() {}
+
+// List users
+//
+// @Operation GET /users - List users
+//     @Response 200
+//         Users.
+func listUsers() {}
+`, parser.ParseComments)
+		psr.Process(fset, file)
+
+		sourceMap := psr.SourceMap()
+		lookup := func(pointer string) int {
+			source, ok := sourceMap.Lookup(pointer)
+			if !ok {
+				return 0
+			}
+			return source.Position.Line
+		}
+
+		So(lookup("/info/version"), ShouldEqual, 4)
+		So(lookup("/paths/~1user~1{id}/patch"), ShouldEqual, 11)
+		So(lookup("/paths/~1user~1{id}/patch/responses"), ShouldEqual, 11)
+		So(lookup("/paths/~1user~1{id}/patch/callbacks/user_updated"), ShouldEqual, 12)
+		So(lookup("/paths/~1user~1{id}/patch/callbacks/user_updated/~1user_updated/post/responses/200"), ShouldEqual, 14)
+		So(lookup("/paths/~1users/get/responses/200/description"), ShouldEqual, 22)
+		So(lookup("/paths/~1user~1{id}"), ShouldEqual, 11)
+		So(lookup("/paths"), ShouldEqual, 0)
+		So(lookup(""), ShouldEqual, 0)
+
+		source, _ := sourceMap.Lookup("/paths/~1user~1{id}/patch")
+		So(source.Ignore, ShouldResemble, []string{"operation-tags", "operation-4xx-response"})
+		So(source.Ignores("operation-tags"), ShouldBeTrue)
+		So(source.Ignores("path-kebab-case"), ShouldBeFalse)
+		So(source.Position.Column, ShouldEqual, 2)
+
+		source, _ = sourceMap.Lookup("/paths/~1users/get")
+		So(source.Position.Line, ShouldEqual, 21)
+		So(source.Position.Column, ShouldEqual, 4)
+
+		oapi, errs := psr.End()
+		So(errs, ShouldBeEmpty)
+		So(oapi.Paths["/user/{id}"].Patch.Description, ShouldEqual, "")
+	})
+}
+
+func TestExternalFiles(t *testing.T) {
+	Convey("External files", t, func() {
+		dir, err := ioutil.TempDir("", "processor")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		files := map[string]string{
+			"schemas/user.json": `{
+	"$id": "#User",
+	"type": "object",
+	"properties": { "manager": { "$ref": "#User" } }
+}`,
+			"schemas/name.yaml":   "type: string\nmaxLength: 10\n",
+			"examples/user.yaml":  "manager: {}\n",
+			"examples/name.json":  `"Test"`,
+			"examples/page.json":  `{"limit": 10, "ratio": 0.5}`,
+			"examples/page.yaml":  "limit: 10\nratio: 0.5\n",
+			"examples/error.json": "{\n\t\"name\": \"Test\",\n}",
+			"examples/error.yaml": "name: [Test\n",
+		}
+		for name, content := range files {
+			file := filepath.Join(dir, filepath.FromSlash(name))
+			So(os.MkdirAll(filepath.Dir(file), 0755), ShouldBeNil)
+			So(ioutil.WriteFile(file, []byte(content), 0644), ShouldBeNil)
+		}
+
+		process := func(src string) (*openapi3.OpenAPIObject, []string) {
+			psr := New()
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, filepath.Join(dir, "api.go"), src, parser.ParseComments)
+			So(err, ShouldBeNil)
+			psr.Process(fset, file)
+			oapi, errs := psr.End()
+			var messages []string
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+			return oapi, messages
+		}
+
+		Convey("should load schemas and examples", func() {
+			oapi, errs := process(`package main
+
+/*
+	@Operation GET /users/{name} - Get user
+		@Parameter name path
+			@JSONSchema file:schemas/name.yaml
+			@JSONExample Test - Test name file:examples/name.json
+		@Response 200
+			OK
+			@JSONSchema {User}
+			@JSONExample Test - Test user file:examples/user.yaml
+*/
+func GetUser() {}
+
+/*
+	@Operation GET /pages - List pages
+		@Response 200
+			OK
+			@JSONExample JSON - file:examples/page.json
+			@JSONExample YAML - file:examples/page.yaml
+*/
+func ListPages() {}
+
+// @JSONSchema file:schemas/user.json
+type User struct{}
+`)
+			So(errs, ShouldBeEmpty)
+			So(*oapi.Components.Schemas["User"], ShouldResemble, openapi3.Schema(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"manager": map[string]interface{}{"$ref": "#/components/schemas/User"},
+				},
+			}))
+			param := oapi.Paths["/users/{name}"].Get.Parameters[0].(*openapi3.ParameterObject)
+			So(param.Schema, ShouldResemble, openapi3.Schema(map[string]interface{}{"type": "string", "maxLength": 10.0}))
+			So(param.Examples["Test"], ShouldResemble, openapi3.ExampleObject{Summary: "Test name", Value: "Test"})
+			response := oapi.Paths["/users/{name}"].Get.Responses["200"].(*openapi3.ResponseObject)
+			So(response.Content["application/json"].Examples["Test"], ShouldResemble, openapi3.ExampleObject{
+				Summary: "Test user",
+				Value:   map[string]interface{}{"manager": map[string]interface{}{}},
+			})
+			page := map[string]interface{}{"limit": 10.0, "ratio": 0.5}
+			examples := oapi.Paths["/pages"].Get.Responses["200"].(*openapi3.ResponseObject).Content["application/json"].Examples
+			So(examples, ShouldResemble, map[string]openapi3.ExampleObject{
+				"JSON": {Value: page},
+				"YAML": {Value: page},
+			})
+		})
+
+		Convey("should report errors of files", func() {
+			_, errs := process(`package main
+
+/*
+	@Operation GET /users - List users
+		@Response 200
+			OK
+			@JSONExample JSON - Invalid JSON file:examples/error.json
+			@JSONExample YAML - Invalid YAML file:examples/error.yaml
+			@JSONExample Missing - Missing file:examples/missing.json
+*/
+func ListUsers() {}
+`)
+			So(errs, ShouldResemble, []string{
+				filepath.Join(dir, "api.go") + ":7:4: invalid json example: " +
+					filepath.Join(dir, "examples/error.json") + ":3:1: invalid character '}' looking for beginning of object key string",
+				filepath.Join(dir, "api.go") + ":8:4: invalid json example: " +
+					filepath.Join(dir, "examples/error.yaml") + ":1: did not find expected ',' or ']'",
+				filepath.Join(dir, "api.go") + ":9:4: invalid json example: open " +
+					filepath.Join(dir, "examples/missing.json") + ": no such file or directory",
+			})
+		})
+	})
+}
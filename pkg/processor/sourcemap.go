@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// IgnoreDirective suppresses lint rules for objects produced by a
+// declaration, e.g. "//openapi3-gen:ignore operation-tags".
+const IgnoreDirective = "openapi3-gen:ignore"
+
+// Source is the annotation producing an object in the generated document.
+type Source struct {
+	Position token.Position
+	// Ignore is the lint rules suppressed in the declaration.
+	Ignore []string
+}
+
+// Ignores returns whether the lint rule is suppressed in the declaration.
+func (s Source) Ignores(rule string) bool {
+	for _, ignored := range s.Ignore {
+		if ignored == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceMap maps JSON pointers of objects in the generated document to the
+// annotations producing them.
+type SourceMap map[string]Source
+
+// Lookup returns the source of the innermost object containing the value at
+// the JSON pointer.
+func (m SourceMap) Lookup(pointer string) (Source, bool) {
+	for {
+		if source, ok := m[pointer]; ok {
+			return source, true
+		}
+		i := strings.LastIndex(pointer, "/")
+		if i < 0 {
+			return Source{}, false
+		}
+		pointer = pointer[:i]
+	}
+}
+
+// commentLines returns the lines of the doc comment as returned by Text, and
+// their positions. Lines not found in the comment are at the fallback
+// position.
+func commentLines(fset *token.FileSet, doc *ast.CommentGroup, fallback token.Position) ([]string, []token.Position) {
+	lines := strings.Split(doc.Text(), "\n")
+	positions := make([]token.Position, len(lines))
+
+	type rawLine struct {
+		text string
+		pos  token.Pos
+	}
+	var raw []rawLine
+	if doc != nil {
+		for _, c := range doc.List {
+			offset := 2
+			text := c.Text[2:]
+			if c.Text[1] == '*' {
+				text = text[:len(text)-2]
+			}
+			for _, line := range strings.Split(text, "\n") {
+				indent := len(line) - len(strings.TrimLeft(line, " \t"))
+				raw = append(raw, rawLine{
+					text: strings.TrimSpace(line),
+					pos:  c.Pos() + token.Pos(offset+indent),
+				})
+				offset += len(line) + 1
+			}
+		}
+	}
+
+	// Lines of Text are the raw lines in order, with some blank and
+	// directive lines removed.
+	next := 0
+	for i, line := range lines {
+		positions[i] = fallback
+		line = strings.TrimSpace(line)
+		for j := next; j < len(raw); j++ {
+			if raw[j].text == line {
+				positions[i] = fset.Position(raw[j].pos)
+				next = j + 1
+				break
+			}
+		}
+	}
+	return lines, positions
+}
+
+// parseIgnoreDirectives removes ignore directives from the doc comment lines
+// and their positions, returning the remaining lines and the suppressed rules.
+func parseIgnoreDirectives(lines []string, positions []token.Position) (docLines []string, docPositions []token.Position, ignore []string) {
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, IgnoreDirective) {
+			ignore = append(ignore, strings.Fields(strings.TrimPrefix(trimmed, IgnoreDirective))...)
+			continue
+		}
+		docLines = append(docLines, line)
+		docPositions = append(docPositions, positions[i])
+	}
+	return
+}
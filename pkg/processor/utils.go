@@ -86,6 +86,13 @@ func translateJSONSchema(json interface{}) interface{} {
 	}
 }
 
+func setExtension(extensions *map[string]interface{}, key string, value interface{}) {
+	if *extensions == nil {
+		*extensions = map[string]interface{}{}
+	}
+	(*extensions)[key] = value
+}
+
 func matchRegex(str string, re *regexp.Regexp) (matches []string, success bool) {
 	matches = re.FindStringSubmatch(str)
 	if len(matches) == 0 {
@@ -0,0 +1,59 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// Problem is a problem details response (RFC 7807).
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Errors are the violations of invalid requests.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// WriteProblem writes a problem details response of the status, with the
+// violations as errors.
+func WriteProblem(w http.ResponseWriter, status int, detail string, violations []Violation) {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+	for _, violation := range violations {
+		problem.Errors = append(problem.Errors, violation.Error())
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// BasePath returns the path of the first server URL of the document, without
+// trailing slash, or an empty string.
+func BasePath(oapi *openapi3.OpenAPIObject) string {
+	if len(oapi.Servers) == 0 {
+		return ""
+	}
+	u, err := url.Parse(oapi.Servers[0].URL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// StripBasePath returns the path relative to the base path, or the path if
+// it is not under the base path.
+func StripBasePath(path string, basePath string) string {
+	if basePath != "" && strings.HasPrefix(path, basePath+"/") {
+		return strings.TrimPrefix(path, basePath)
+	}
+	return path
+}
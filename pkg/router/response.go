@@ -0,0 +1,132 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+type response struct {
+	headers []*header
+	content map[string]*jsonschema.Schema
+}
+
+type header struct {
+	name     string
+	required bool
+	// schema is nil if the header has no schema.
+	schema *jsonschema.Schema
+	types  []string
+}
+
+func (c *compiler) response(pointer string, responseObj *openapi3.ResponseObject) (*response, error) {
+	content, err := c.content(pointer, responseObj.Content)
+	if err != nil {
+		return nil, err
+	}
+	r := &response{content: content}
+
+	for _, name := range openapi3.SortedKeys(responseObj.Headers) {
+		// Content-Type is described by the content of the response.
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		headerPointer := pointer + openapi3.Pointer("headers", name)
+		obj, _ := responseObj.Headers[name].(map[string]interface{})
+		if ref, ok := obj["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+			headerPointer = ref[1:]
+			resolved, err := jsonschema.ResolvePointer(c.doc, headerPointer)
+			if err != nil {
+				return nil, err
+			}
+			obj, _ = resolved.(map[string]interface{})
+		}
+
+		h := &header{name: name}
+		h.required, _ = obj["required"].(bool)
+		if obj["schema"] != nil {
+			h.schema, err = c.compiler.Compile("#" + headerPointer + "/schema")
+			if err != nil {
+				return nil, err
+			}
+			resolved, _ := c.oapi.ResolveSchema(obj["schema"])
+			h.types = schemaTypes(resolved)
+		}
+		r.headers = append(r.headers, h)
+	}
+	return r, nil
+}
+
+// ValidateResponse validates the status code, headers and body of a response
+// to the route.
+func (route *Route) ValidateResponse(status int, headers http.Header, body []byte) []Violation {
+	fail := func(in string, name string, format string, args ...interface{}) []Violation {
+		return []Violation{{In: in, Name: name, ValidationError: jsonschema.ValidationError{
+			Message: fmt.Sprintf(format, args...),
+		}}}
+	}
+
+	code := strconv.Itoa(status)
+	var r *response
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if r = route.responses[key]; r != nil {
+			break
+		}
+	}
+	if r == nil {
+		return fail("status", "", "status code %d is not declared", status)
+	}
+
+	var violations []Violation
+	for _, h := range r.headers {
+		value := headers.Get(h.name)
+		if value == "" {
+			if h.required {
+				violations = append(violations, fail("header", h.name, "is required")...)
+			}
+			continue
+		}
+		if h.schema != nil {
+			for _, err := range h.schema.Validate(convertValue(value, h.types)) {
+				violations = append(violations, Violation{In: "header", Name: h.name, ValidationError: err})
+			}
+		}
+	}
+
+	if len(body) == 0 {
+		return violations
+	}
+	if len(r.content) == 0 {
+		return append(violations, fail("body", "", "no content is declared")...)
+	}
+	mediaType, _, err := mime.ParseMediaType(headers.Get("Content-Type"))
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+	key, ok := MatchMediaType(r.content, mediaType)
+	if !ok {
+		return append(violations, fail("header", "Content-Type", "media type %s is not declared", mediaType)...)
+	}
+	schema := r.content[key]
+	if schema == nil || !IsJSONMediaType(mediaType) {
+		return violations
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return append(violations, fail("body", "", "invalid JSON: %v", err)...)
+	}
+	for _, err := range schema.Validate(value) {
+		violations = append(violations, Violation{In: "body", ValidationError: err})
+	}
+	return violations
+}
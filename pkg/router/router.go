@@ -0,0 +1,122 @@
+package router
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+var (
+	// ErrNotFound is returned by Match if no path matches the request.
+	ErrNotFound = errors.New("path not found")
+	// ErrMethodNotAllowed is returned by Match if the path matches, but
+	// without an operation of the method.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+)
+
+var templateVariable = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Router routes requests to operations of an OpenAPI document, with schemas
+// of their parameters, request bodies and responses compiled.
+type Router struct {
+	routes []*Route
+}
+
+// Route is an operation of the document.
+type Route struct {
+	Method    string
+	Path      string
+	Operation *openapi3.OperationObject
+
+	pattern *regexp.Regexp
+	// names are the names of template variables captured by the pattern.
+	names []string
+	// literals is the length of the path excluding template variables;
+	// routes with longer literals are matched first.
+	literals int
+
+	parameters   []*parameter
+	bodyRequired bool
+	body         map[string]*jsonschema.Schema
+	responses    map[string]*response
+}
+
+// New compiles the routes of the document.
+func New(oapi *openapi3.OpenAPIObject) (*Router, error) {
+	doc, err := openapi3.ToDocument(oapi)
+	if err != nil {
+		return nil, err
+	}
+	c := &compiler{oapi: oapi, doc: doc, compiler: jsonschema.NewOpenAPICompiler(doc)}
+
+	r := &Router{}
+	for _, path := range openapi3.SortedKeys(oapi.Paths) {
+		item := oapi.Paths[path]
+		for _, method := range openapi3.Methods {
+			op := item.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			route, err := c.route(path, method, op)
+			if err != nil {
+				return nil, err
+			}
+			r.routes = append(r.routes, route)
+		}
+	}
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return r.routes[i].literals > r.routes[j].literals
+	})
+	return r, nil
+}
+
+// Match returns the route of the request method and path, and the values of
+// its path parameters.
+func (r *Router) Match(method string, path string) (*Route, map[string]string, error) {
+	pathMatched := false
+	for _, route := range r.routes {
+		matches := route.pattern.FindStringSubmatch(path)
+		if matches == nil {
+			continue
+		}
+		pathMatched = true
+		if route.Method != method {
+			continue
+		}
+
+		params := map[string]string{}
+		for i, name := range route.names {
+			params[name] = matches[i+1]
+		}
+		return route, params, nil
+	}
+	if pathMatched {
+		return nil, nil, ErrMethodNotAllowed
+	}
+	return nil, nil, ErrNotFound
+}
+
+// pathPattern returns the pattern of the path template capturing the values
+// of template variables, their names, and the length of its literals.
+func pathPattern(path string) (*regexp.Regexp, []string, int) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	var names []string
+	literals := 0
+	last := 0
+	for _, loc := range templateVariable.FindAllStringSubmatchIndex(path, -1) {
+		pattern.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		literals += loc[0] - last
+		pattern.WriteString("([^/]+)")
+		names = append(names, path[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(path[last:]))
+	literals += len(path) - last
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String()), names, literals
+}
@@ -0,0 +1,132 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+paths:
+  /users:
+    get:
+      parameters:
+      - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+      - {name: ids, in: query, schema: {type: array, items: {type: integer}}}
+      - {name: X-Request-ID, in: header, required: true, schema: {type: string}}
+      responses:
+        "200": {description: OK}
+    post:
+      requestBody:
+        $ref: '#/components/requestBodies/User'
+      responses:
+        "201": {description: Created}
+  /users/{id}:
+    parameters:
+    - {name: id, in: path, required: true, schema: {type: string, format: uuid}}
+    get:
+      responses:
+        "200": {description: OK}
+  /users/me:
+    get:
+      responses:
+        "200": {description: OK}
+components:
+  requestBodies:
+    User:
+      required: true
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/User'}
+  schemas:
+    User:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        nickname: {type: string, nullable: true}
+`
+
+func TestRouter(t *testing.T) {
+	Convey("Router", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+		r, err := New(oapi)
+		So(err, ShouldBeNil)
+
+		validate := func(req *http.Request) []string {
+			route, params, err := r.Match(req.Method, req.URL.Path)
+			So(err, ShouldBeNil)
+			violations, err := route.ValidateRequest(req, params)
+			So(err, ShouldBeNil)
+			var messages []string
+			for _, violation := range violations {
+				messages = append(messages, violation.Error())
+			}
+			return messages
+		}
+
+		Convey("should match routes", func() {
+			route, params, err := r.Match("GET", "/users/me")
+			So(err, ShouldBeNil)
+			So(route.Path, ShouldEqual, "/users/me")
+			So(params, ShouldBeEmpty)
+
+			route, params, err = r.Match("GET", "/users/a")
+			So(err, ShouldBeNil)
+			So(route.Path, ShouldEqual, "/users/{id}")
+			So(params, ShouldResemble, map[string]string{"id": "a"})
+
+			_, _, err = r.Match("DELETE", "/users/a")
+			So(err, ShouldEqual, ErrMethodNotAllowed)
+			_, _, err = r.Match("GET", "/users/a/b")
+			So(err, ShouldEqual, ErrNotFound)
+		})
+
+		Convey("should validate parameters", func() {
+			req := httptest.NewRequest("GET", "/users?limit=10&ids=1&ids=2", nil)
+			req.Header.Set("X-Request-ID", "abc")
+			So(validate(req), ShouldBeEmpty)
+
+			req = httptest.NewRequest("GET", "/users?limit=1000&ids=1,a", nil)
+			So(validate(req), ShouldResemble, []string{
+				"query limit: must be less than or equal to 100",
+				"query ids/1: must be integer, got string",
+				"header X-Request-ID: is required",
+			})
+
+			req = httptest.NewRequest("GET", "/users/a", nil)
+			So(validate(req), ShouldResemble, []string{
+				"path id: must be a valid uuid",
+			})
+		})
+
+		Convey("should validate body", func() {
+			req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name": "Test", "nickname": null}`))
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+			So(validate(req), ShouldBeEmpty)
+
+			req = httptest.NewRequest("POST", "/users", strings.NewReader(`{"nickname": 1}`))
+			req.Header.Set("Content-Type", "application/json")
+			So(validate(req), ShouldResemble, []string{
+				"body: missing required property \"name\"",
+				"body/nickname: must be string, got integer",
+			})
+
+			req = httptest.NewRequest("POST", "/users", strings.NewReader(`name=Test`))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			So(validate(req), ShouldResemble, []string{
+				"header Content-Type: media type application/x-www-form-urlencoded is not supported",
+			})
+
+			req = httptest.NewRequest("POST", "/users", nil)
+			So(validate(req), ShouldResemble, []string{"body: is required"})
+		})
+	})
+}
@@ -0,0 +1,355 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// Violation is a value of a request or response violating the operation.
+type Violation struct {
+	// In is the location of the value: path, query, header, cookie, body, or
+	// status of responses.
+	In string
+	// Name is the name of the parameter or header; empty for bodies.
+	Name string
+	jsonschema.ValidationError
+}
+
+func (v Violation) Error() string {
+	if v.Name == "" {
+		return fmt.Sprintf("%s%s: %s", v.In, v.Pointer, v.Message)
+	}
+	return fmt.Sprintf("%s %s%s: %s", v.In, v.Name, v.Pointer, v.Message)
+}
+
+type parameter struct {
+	*openapi3.ParameterObject
+	// schema is nil if the parameter has no schema.
+	schema *jsonschema.Schema
+	// types and itemTypes are the types of the schema and its items, to
+	// convert values of the parameter.
+	types     []string
+	itemTypes []string
+}
+
+type compiler struct {
+	oapi     *openapi3.OpenAPIObject
+	doc      interface{}
+	compiler *jsonschema.Compiler
+}
+
+// schema compiles the schema at the pointer in the document, or returns nil
+// if there is no schema.
+func (c *compiler) schema(pointer string, schema openapi3.Schema) (*jsonschema.Schema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	return c.compiler.Compile("#" + pointer)
+}
+
+func (c *compiler) route(path string, method string, op *openapi3.OperationObject) (*Route, error) {
+	pattern, names, literals := pathPattern(path)
+	route := &Route{
+		Method:    method,
+		Path:      path,
+		Operation: op,
+		pattern:   pattern,
+		names:     names,
+		literals:  literals,
+	}
+
+	pathPointer := openapi3.Pointer("paths", path)
+	operationPointer := pathPointer + openapi3.Pointer(strings.ToLower(method))
+	item := c.oapi.Paths[path]
+	var params []*parameter
+	for i := range item.Parameters {
+		param, err := c.parameter(pathPointer+openapi3.Pointer("parameters", strconv.Itoa(i)), &item.Parameters[i])
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+	for i, p := range op.Parameters {
+		pointer := operationPointer + openapi3.Pointer("parameters", strconv.Itoa(i))
+		if id, ok := openapi3.RefID(p, "#/components/parameters/"); ok {
+			pointer = openapi3.Pointer("components", "parameters", id)
+		}
+		paramObj := c.oapi.ResolveParameter(p)
+		if paramObj == nil {
+			return nil, fmt.Errorf("%s: cannot resolve parameter", pointer)
+		}
+		param, err := c.parameter(pointer, paramObj)
+		if err != nil {
+			return nil, err
+		}
+		// Operation parameters override path item parameters.
+		for j, existing := range params {
+			if existing.Name == param.Name && existing.Location == param.Location {
+				params = append(params[:j], params[j+1:]...)
+				break
+			}
+		}
+		params = append(params, param)
+	}
+	route.parameters = params
+
+	if op.RequestBody != nil {
+		pointer := operationPointer + "/requestBody"
+		if id, ok := openapi3.RefID(op.RequestBody, "#/components/requestBodies/"); ok {
+			pointer = openapi3.Pointer("components", "requestBodies", id)
+		}
+		body := c.oapi.ResolveRequestBody(op.RequestBody)
+		if body == nil {
+			return nil, fmt.Errorf("%s: cannot resolve request body", pointer)
+		}
+		content, err := c.content(pointer, body.Content)
+		if err != nil {
+			return nil, err
+		}
+		route.bodyRequired = body.Required
+		route.body = content
+	}
+
+	route.responses = map[string]*response{}
+	for code, r := range op.Responses {
+		pointer := operationPointer + openapi3.Pointer("responses", code)
+		if id, ok := openapi3.RefID(r, "#/components/responses/"); ok {
+			pointer = openapi3.Pointer("components", "responses", id)
+		}
+		responseObj := c.oapi.ResolveResponse(r)
+		if responseObj == nil {
+			return nil, fmt.Errorf("%s: cannot resolve response", pointer)
+		}
+		compiled, err := c.response(pointer, responseObj)
+		if err != nil {
+			return nil, err
+		}
+		route.responses[code] = compiled
+	}
+	return route, nil
+}
+
+func (c *compiler) parameter(pointer string, param *openapi3.ParameterObject) (*parameter, error) {
+	schema, err := c.schema(pointer+"/schema", param.Schema)
+	if err != nil {
+		return nil, err
+	}
+	resolved, _ := c.oapi.ResolveSchema(param.Schema)
+	items, _ := c.oapi.ResolveSchema(resolved["items"])
+	return &parameter{
+		ParameterObject: param,
+		schema:          schema,
+		types:           schemaTypes(resolved),
+		itemTypes:       schemaTypes(items),
+	}, nil
+}
+
+// content compiles the schemas of the media types of the content at the
+// pointer.
+func (c *compiler) content(pointer string, content map[string]openapi3.MediaTypeObject) (map[string]*jsonschema.Schema, error) {
+	schemas := map[string]*jsonschema.Schema{}
+	for mediaType, mt := range content {
+		schema, err := c.schema(pointer+openapi3.Pointer("content", mediaType, "schema"), mt.Schema)
+		if err != nil {
+			return nil, err
+		}
+		schemas[mediaType] = schema
+	}
+	return schemas, nil
+}
+
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, value := range t {
+			if str, ok := value.(string); ok {
+				types = append(types, str)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// ValidateRequest validates the parameters and body of the request matched
+// to the route, with the values of path parameters returned by Match. The
+// body of the request is restored to be read again.
+func (route *Route) ValidateRequest(r *http.Request, pathParams map[string]string) ([]Violation, error) {
+	var violations []Violation
+	fail := func(in string, name string, errs ...jsonschema.ValidationError) {
+		for _, err := range errs {
+			violations = append(violations, Violation{In: in, Name: name, ValidationError: err})
+		}
+	}
+
+	query := r.URL.Query()
+	for _, param := range route.parameters {
+		var values []string
+		switch param.Location {
+		case openapi3.ParameterLocationPath:
+			if value, ok := pathParams[param.Name]; ok {
+				values = []string{value}
+			}
+		case openapi3.ParameterLocationQuery:
+			values = query[param.Name]
+		case openapi3.ParameterLocationHeader:
+			values = r.Header.Values(param.Name)
+		case openapi3.ParameterLocationCookie:
+			if cookie, err := r.Cookie(param.Name); err == nil {
+				values = []string{cookie.Value}
+			}
+		}
+
+		in := string(param.Location)
+		if len(values) == 0 {
+			if param.Required {
+				fail(in, param.Name, jsonschema.ValidationError{Message: "is required"})
+			}
+			continue
+		}
+		if param.schema == nil || containsString(param.types, "object") {
+			continue
+		}
+		fail(in, param.Name, param.schema.Validate(param.value(values))...)
+	}
+
+	bodyViolations, err := route.validateBody(r)
+	if err != nil {
+		return nil, err
+	}
+	return append(violations, bodyViolations...), nil
+}
+
+// value converts the values of the parameter to the types of its schema.
+// Values of arrays are repeated, or separated by commas.
+func (param *parameter) value(values []string) interface{} {
+	if !containsString(param.types, "array") {
+		return convertValue(values[0], param.types)
+	}
+	explode := param.Location == openapi3.ParameterLocationQuery || param.Location == openapi3.ParameterLocationCookie
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+	if !explode || len(values) == 1 {
+		values = strings.Split(values[0], ",")
+	}
+	items := make([]interface{}, len(values))
+	for i, value := range values {
+		items[i] = convertValue(value, param.itemTypes)
+	}
+	return items
+}
+
+// convertValue converts a string value to a number or boolean if the schema
+// allows it, or returns the string otherwise.
+func convertValue(value string, types []string) interface{} {
+	if containsString(types, "integer") || containsString(types, "number") {
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	if containsString(types, "boolean") {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func (route *Route) validateBody(r *http.Request) ([]Violation, error) {
+	if route.body == nil {
+		return nil, nil
+	}
+
+	var data []byte
+	if r.Body != nil {
+		var err error
+		data, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+	if len(data) == 0 {
+		if route.bodyRequired {
+			return []Violation{{In: "body", ValidationError: jsonschema.ValidationError{Message: "is required"}}}, nil
+		}
+		return nil, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+	key, ok := MatchMediaType(route.body, mediaType)
+	if !ok {
+		return []Violation{{In: "header", Name: "Content-Type", ValidationError: jsonschema.ValidationError{
+			Message: fmt.Sprintf("media type %s is not supported", mediaType),
+		}}}, nil
+	}
+	schema := route.body[key]
+	if schema == nil || !IsJSONMediaType(mediaType) {
+		return nil, nil
+	}
+
+	var body interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		return []Violation{{In: "body", ValidationError: jsonschema.ValidationError{
+			Message: fmt.Sprintf("invalid JSON: %v", err),
+		}}}, nil
+	}
+
+	var violations []Violation
+	for _, err := range schema.Validate(body) {
+		violations = append(violations, Violation{In: "body", ValidationError: err})
+	}
+	return violations, nil
+}
+
+// MatchMediaType returns the key of the content matching the media type:
+// the media type, a range of its type (e.g. "image/*"), or "*/*".
+func MatchMediaType(content interface{}, mediaType string) (string, bool) {
+	keys := openapi3.SortedKeys(content)
+	candidates := []string{mediaType, "*/*"}
+	if i := strings.Index(mediaType, "/"); i >= 0 {
+		candidates = []string{mediaType, mediaType[:i] + "/*", "*/*"}
+	}
+	for _, candidate := range candidates {
+		for _, key := range keys {
+			if strings.EqualFold(key, candidate) {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsJSONMediaType reports whether the media type is JSON, e.g.
+// "application/json" or "application/problem+json".
+func IsJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func containsString(values []string, str string) bool {
+	for _, value := range values {
+		if value == str {
+			return true
+		}
+	}
+	return false
+}
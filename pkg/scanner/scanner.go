@@ -1,18 +1,32 @@
 package scanner
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 
 	"golang.org/x/tools/go/packages"
 )
 
 type ScannerHandler func(*token.FileSet, *ast.File)
 
+// Package is a type-checked package of scanned files.
+type Package struct {
+	Fset  *token.FileSet
+	Path  string
+	Files []*ast.File
+	Types *types.Package
+	Info  *types.Info
+}
+
+type PackageHandler func(*Package)
+
 type Scanner struct {
-	fset    *token.FileSet
-	handler ScannerHandler
+	fset       *token.FileSet
+	handler    ScannerHandler
+	pkgHandler PackageHandler
 }
 
 func New(handler ScannerHandler) *Scanner {
@@ -22,10 +36,21 @@ func New(handler ScannerHandler) *Scanner {
 	}
 }
 
+// HandlePackages enables type checking of scanned packages. The handler is
+// called for each package after its files are passed to the file handler.
+func (scn *Scanner) HandlePackages(handler PackageHandler) {
+	scn.pkgHandler = handler
+}
+
 func (scn *Scanner) Scan(dir string, patterns []string) error {
 	pkgConfig := packages.Config{
 		Dir:  dir,
-		Mode: packages.NeedFiles,
+		Fset: scn.fset,
+		Mode: packages.NeedName | packages.NeedFiles,
+	}
+	if scn.pkgHandler != nil {
+		pkgConfig.Mode |= packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedTypesSizes | packages.NeedSyntax | packages.NeedTypesInfo
 	}
 
 	pkgs, err := packages.Load(&pkgConfig, patterns...)
@@ -34,13 +59,38 @@ func (scn *Scanner) Scan(dir string, patterns []string) error {
 	}
 
 	for _, pkg := range pkgs {
+		// Annotations are read from the same source files in both modes;
+		// syntax trees of type-checked files are reused.
+		syntax := map[string]*ast.File{}
+		for _, file := range pkg.Syntax {
+			syntax[scn.fset.Position(file.Pos()).Filename] = file
+		}
+
+		var files []*ast.File
 		for _, file := range pkg.GoFiles {
-			astFile, err := parser.ParseFile(scn.fset, file, nil, parser.ParseComments)
-			if err != nil {
-				return err
+			astFile, ok := syntax[file]
+			if !ok {
+				astFile, err = parser.ParseFile(scn.fset, file, nil, parser.ParseComments)
+				if err != nil {
+					return err
+				}
 			}
 
 			scn.handler(scn.fset, astFile)
+			files = append(files, astFile)
+		}
+
+		if scn.pkgHandler != nil {
+			if len(pkg.Errors) > 0 {
+				return fmt.Errorf("%s", pkg.Errors[0])
+			}
+			scn.pkgHandler(&Package{
+				Fset:  scn.fset,
+				Path:  pkg.PkgPath,
+				Files: pkg.Syntax,
+				Types: pkg.Types,
+				Info:  pkg.TypesInfo,
+			})
 		}
 	}
 
@@ -0,0 +1,277 @@
+package split
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"gopkg.in/yaml.v2"
+)
+
+// Bundle loads the document file, inlining the contents of external
+// references. References to files of components of the root document, as
+// written by Files, are restored to internal references to the components.
+func Bundle(file string) (yaml.MapSlice, error) {
+	rootFile, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	b := &bundler{
+		rootFile:   rootFile,
+		docs:       map[string]yaml.MapSlice{},
+		components: map[string]string{},
+		inlining:   map[string]bool{},
+	}
+	root, err := b.load(rootFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range root {
+		if item.Key != "components" {
+			continue
+		}
+		kinds, _ := item.Value.(yaml.MapSlice)
+		for _, kind := range kinds {
+			components, _ := kind.Value.(yaml.MapSlice)
+			for _, component := range components {
+				ref, ok := refOf(component.Value)
+				if !ok || strings.HasPrefix(ref, "#") {
+					continue
+				}
+				target, err := b.target(rootFile, ref)
+				if err != nil {
+					return nil, err
+				}
+				b.components[target] = openapi3.Pointer("components", fmt.Sprint(kind.Key), fmt.Sprint(component.Key))
+			}
+		}
+	}
+
+	bundled, err := b.bundle(root, keywordContext, rootFile, "")
+	if err != nil {
+		return nil, err
+	}
+	return bundled.(yaml.MapSlice), nil
+}
+
+type bundler struct {
+	rootFile string
+	// docs are the loaded documents of absolute file paths.
+	docs map[string]yaml.MapSlice
+	// components are the pointers of components in the root document of
+	// their targets.
+	components map[string]string
+	// inlining records the targets being inlined, to detect circular
+	// references.
+	inlining map[string]bool
+}
+
+// load loads the document of the file, which must be an object.
+func (b *bundler) load(file string) (yaml.MapSlice, error) {
+	if doc, ok := b.docs[file]; ok {
+		return doc, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrapf(err, "failed to load %s", file)
+	}
+	b.docs[file] = doc
+	return doc, nil
+}
+
+// target returns the target of the reference in the file: the absolute path
+// of the referenced file, and the fragment.
+func (b *bundler) target(file string, ref string) (string, error) {
+	if strings.Contains(ref, "://") {
+		return "", fmt.Errorf("%s: remote reference %s is not supported", file, ref)
+	}
+	parts := strings.SplitN(ref, "#", 2)
+	target := file
+	if parts[0] != "" {
+		name, err := url.PathUnescape(parts[0])
+		if err != nil {
+			return "", fmt.Errorf("%s: invalid reference %s", file, ref)
+		}
+		target = filepath.Join(filepath.Dir(file), filepath.FromSlash(name))
+	}
+	if len(parts) == 2 && parts[1] != "" {
+		return target + "#" + parts[1], nil
+	}
+	return target, nil
+}
+
+// internalRef returns the internal reference of the target, if it is in the
+// root document or a component file.
+func (b *bundler) internalRef(target string, pointer string) (string, bool) {
+	file, fragment := target, ""
+	if i := strings.Index(target, "#"); i >= 0 {
+		file, fragment = target[:i], target[i+1:]
+	}
+	if file == b.rootFile {
+		return "#" + fragment, true
+	}
+	if component, ok := b.components[target]; ok && component != pointer {
+		return "#" + component, true
+	}
+	if component, ok := b.components[file]; ok && fragment != "" {
+		return "#" + component + fragment, true
+	}
+	return "", false
+}
+
+// bundle returns the value of the context in the file at the pointer of the
+// bundled document, with external references inlined or restored to
+// internal references.
+func (b *bundler) bundle(value interface{}, ctx context, file string, pointer string) (interface{}, error) {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		if ref, ok := refOf(v); ok && ctx == keywordContext && (file != b.rootFile || !strings.HasPrefix(ref, "#")) {
+			return b.inline(file, ref, pointer)
+		}
+		obj := make(yaml.MapSlice, len(v))
+		for i, item := range v {
+			obj[i] = item
+			key := fmt.Sprint(item.Key)
+			switch {
+			case ctx.isMapping(key):
+				mapping, err := b.mapping(item.Value, file)
+				if err != nil {
+					return nil, err
+				}
+				obj[i].Value = mapping
+			case ctx.isExample(key):
+				break
+			default:
+				bundled, err := b.bundle(item.Value, ctx.child(key), file, pointer+openapi3.Pointer(key))
+				if err != nil {
+					return nil, err
+				}
+				obj[i].Value = bundled
+			}
+		}
+		return obj, nil
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, value := range v {
+			bundled, err := b.bundle(value, keywordContext, file, pointer+openapi3.Pointer(strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = bundled
+		}
+		return arr, nil
+	default:
+		return value, nil
+	}
+}
+
+// inline returns the internal reference of the reference in the file, or
+// the bundled value of its target.
+func (b *bundler) inline(file string, ref string, pointer string) (interface{}, error) {
+	target, err := b.target(file, ref)
+	if err != nil {
+		return nil, err
+	}
+	if internal, ok := b.internalRef(target, pointer); ok {
+		return yaml.MapSlice{{Key: "$ref", Value: internal}}, nil
+	}
+	if b.inlining[target] {
+		return nil, fmt.Errorf("%s: circular reference %s", file, ref)
+	}
+	b.inlining[target] = true
+	defer delete(b.inlining, target)
+
+	targetFile, fragment := target, ""
+	if i := strings.Index(target, "#"); i >= 0 {
+		targetFile, fragment = target[:i], target[i+1:]
+	}
+	doc, err := b.load(targetFile)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := resolvePointer(doc, fragment)
+	if !ok {
+		return nil, fmt.Errorf("%s: cannot resolve reference %s", file, ref)
+	}
+	return b.bundle(value, keywordContext, targetFile, pointer)
+}
+
+// mapping returns the discriminator mapping with references restored to
+// internal references.
+func (b *bundler) mapping(value interface{}, file string) (interface{}, error) {
+	mapping, ok := value.(yaml.MapSlice)
+	if !ok {
+		return value, nil
+	}
+	bundled := make(yaml.MapSlice, len(mapping))
+	for i, entry := range mapping {
+		bundled[i] = entry
+		ref, ok := entry.Value.(string)
+		if !ok || (file == b.rootFile && strings.HasPrefix(ref, "#")) {
+			continue
+		}
+		target, err := b.target(file, ref)
+		if err != nil {
+			return nil, err
+		}
+		internal, ok := b.internalRef(target, "")
+		if !ok {
+			return nil, fmt.Errorf("%s: mapping %s is not a component", file, ref)
+		}
+		bundled[i].Value = internal
+	}
+	return bundled, nil
+}
+
+func refOf(value interface{}) (string, bool) {
+	obj, ok := value.(yaml.MapSlice)
+	if !ok {
+		return "", false
+	}
+	for _, item := range obj {
+		if item.Key == "$ref" {
+			ref, ok := item.Value.(string)
+			return ref, ok
+		}
+	}
+	return "", false
+}
+
+// resolvePointer returns the value at the JSON pointer in the document.
+func resolvePointer(doc interface{}, pointer string) (interface{}, bool) {
+	value := doc
+	for _, token := range pointerTokens(pointer) {
+		switch v := value.(type) {
+		case yaml.MapSlice:
+			found := false
+			for _, item := range v {
+				if fmt.Sprint(item.Key) == token {
+					value, found = item.Value, true
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,74 @@
+package split
+
+// context is the kind of object whose keys are being visited, so that
+// keywords are told apart from names chosen by the author, e.g. a schema
+// property named example.
+type context int
+
+const (
+	// keywordContext is an object whose keys are keywords.
+	keywordContext context = iota
+	// nameContext is a map of names to objects.
+	nameContext
+	// callbackContext is a map of names to callback objects, which are
+	// maps of expressions to path items.
+	callbackContext
+	// discriminatorContext is a discriminator object.
+	discriminatorContext
+)
+
+// nameMaps are the keywords of maps of names to objects.
+var nameMaps = map[string]bool{
+	"paths":             true,
+	"webhooks":          true,
+	"schemas":           true,
+	"responses":         true,
+	"parameters":        true,
+	"examples":          true,
+	"requestBodies":     true,
+	"headers":           true,
+	"securitySchemes":   true,
+	"links":             true,
+	"pathItems":         true,
+	"content":           true,
+	"encoding":          true,
+	"variables":         true,
+	"properties":        true,
+	"patternProperties": true,
+	"dependentSchemas":  true,
+	"definitions":       true,
+	"$defs":             true,
+}
+
+// child returns the context of the value of the key in an object of the
+// context.
+func (c context) child(key string) context {
+	switch c {
+	case nameContext, discriminatorContext:
+		return keywordContext
+	case callbackContext:
+		return nameContext
+	}
+	switch {
+	case key == "callbacks":
+		return callbackContext
+	case key == "discriminator":
+		return discriminatorContext
+	case nameMaps[key]:
+		return nameContext
+	default:
+		return keywordContext
+	}
+}
+
+// isMapping reports whether the key in an object of the context is a
+// discriminator mapping.
+func (c context) isMapping(key string) bool {
+	return c == discriminatorContext && key == "mapping"
+}
+
+// isExample reports whether the key in an object of the context is an
+// example, whose value is not rewritten.
+func (c context) isExample(key string) bool {
+	return c == keywordContext && key == "example"
+}
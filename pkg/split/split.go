@@ -0,0 +1,222 @@
+package split
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	"gopkg.in/yaml.v2"
+)
+
+// RootFile is the file of the root document of split documents.
+const RootFile = "openapi.yaml"
+
+var pointerTokenUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// Files splits the document into files by path relative to the output
+// directory: the root document openapi.yaml, a file of each path item in
+// paths, and a file of each component in components/<type>, e.g.
+// components/schemas/User.yaml. Internal references are rewritten to
+// references relative to the referencing file.
+func Files(oapi *openapi3.OpenAPIObject) (map[string][]byte, error) {
+	data, err := yaml.Marshal(oapi)
+	if err != nil {
+		return nil, err
+	}
+	var root yaml.MapSlice
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	s := &splitter{targets: map[string]string{}}
+	contents := map[string]interface{}{}
+	used := map[string]bool{}
+	for _, item := range root {
+		entries, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		switch item.Key {
+		case "paths":
+			for j, entry := range entries {
+				key := fmt.Sprint(entry.Key)
+				file := pathFile(key, used)
+				s.targets[openapi3.Pointer("paths", key)] = file
+				contents[file] = entry.Value
+				entries[j].Value = yaml.MapSlice{{Key: "$ref", Value: escapeRef(file)}}
+			}
+		case "components":
+			for _, kind := range entries {
+				components, ok := kind.Value.(yaml.MapSlice)
+				if !ok {
+					continue
+				}
+				for j, component := range components {
+					name := fmt.Sprint(component.Key)
+					file := path.Join("components", fileName(fmt.Sprint(kind.Key)), fileName(name)+".yaml")
+					if _, ok := contents[file]; ok {
+						return nil, fmt.Errorf("component %s of %s has the file of another component: %s", name, kind.Key, file)
+					}
+					s.targets[openapi3.Pointer("components", fmt.Sprint(kind.Key), name)] = file
+					contents[file] = component.Value
+					components[j].Value = yaml.MapSlice{{Key: "$ref", Value: escapeRef(file)}}
+				}
+			}
+		}
+	}
+
+	files := map[string][]byte{}
+	for file, content := range contents {
+		data, err := yaml.Marshal(s.rewrite(content, keywordContext, path.Dir(file)))
+		if err != nil {
+			return nil, err
+		}
+		files[file] = data
+	}
+	data, err = yaml.Marshal(s.rewrite(root, keywordContext, "."))
+	if err != nil {
+		return nil, err
+	}
+	files[RootFile] = data
+	return files, nil
+}
+
+// pathFile returns the file of the path item, e.g. paths/users_{id}.yaml of
+// /users/{id}. Files of paths with the same name are numbered.
+func pathFile(key string, used map[string]bool) string {
+	name := fileName(strings.Trim(key, "/"))
+	if name == "" {
+		name = "root"
+	}
+	file := path.Join("paths", name+".yaml")
+	for i := 2; used[file]; i++ {
+		file = path.Join("paths", fmt.Sprintf("%s_%d.yaml", name, i))
+	}
+	used[file] = true
+	return file
+}
+
+// fileName returns the name with path separators replaced, and . and ..
+// escaped, so that it names a file in the directory.
+func fileName(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if strings.Trim(name, ".") == "" {
+		name = strings.Repeat("_", len(name))
+	}
+	return name
+}
+
+// escapeRef returns the slash-separated path or JSON pointer percent-encoded
+// as a URI reference, e.g. paths/users_%7Bid%7D.yaml.
+func escapeRef(ref string) string {
+	segments := strings.Split(ref, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+type splitter struct {
+	// targets are the files of the pointers of split objects.
+	targets map[string]string
+}
+
+// rewrite returns the value of the context in the directory, with internal
+// references and discriminator mappings rewritten relative to the directory.
+// Examples are not rewritten.
+func (s *splitter) rewrite(value interface{}, ctx context, dir string) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		obj := make(yaml.MapSlice, len(v))
+		for i, item := range v {
+			obj[i] = item
+			key := fmt.Sprint(item.Key)
+			switch {
+			case key == "$ref" && ctx == keywordContext:
+				if ref, ok := item.Value.(string); ok {
+					obj[i].Value = s.ref(ref, dir)
+				}
+			case ctx.isMapping(key):
+				if mapping, ok := item.Value.(yaml.MapSlice); ok {
+					rewritten := make(yaml.MapSlice, len(mapping))
+					for j, entry := range mapping {
+						rewritten[j] = entry
+						if ref, ok := entry.Value.(string); ok {
+							rewritten[j].Value = s.ref(ref, dir)
+						}
+					}
+					obj[i].Value = rewritten
+				}
+			case ctx.isExample(key):
+				break
+			default:
+				obj[i].Value = s.rewrite(item.Value, ctx.child(key), dir)
+			}
+		}
+		return obj
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, value := range v {
+			arr[i] = s.rewrite(value, keywordContext, dir)
+		}
+		return arr
+	default:
+		return value
+	}
+}
+
+// ref returns the internal reference relative to the directory. References
+// to objects not split are references to the root document.
+func (s *splitter) ref(ref string, dir string) string {
+	if !strings.HasPrefix(ref, "#") {
+		return ref
+	}
+	tokens := pointerTokens(ref[1:])
+	for n := len(tokens); n > 0; n-- {
+		file, ok := s.targets[openapi3.Pointer(tokens[:n]...)]
+		if !ok {
+			continue
+		}
+		if rest := openapi3.Pointer(tokens[n:]...); rest != "" {
+			return escapeRef(relativePath(dir, file)) + "#" + escapeRef(rest)
+		}
+		return escapeRef(relativePath(dir, file))
+	}
+	if dir == "." {
+		return ref
+	}
+	return relativePath(dir, RootFile) + ref
+}
+
+// pointerTokens returns the unescaped reference tokens of the JSON pointer.
+func pointerTokens(pointer string) []string {
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = pointerTokenUnescaper.Replace(token)
+	}
+	return tokens
+}
+
+// relativePath returns the slash-separated path of the file relative to the
+// directory.
+func relativePath(dir string, file string) string {
+	if dir == "." {
+		return file
+	}
+	dirs := strings.Split(dir, "/")
+	parts := strings.Split(file, "/")
+	common := 0
+	for common < len(dirs) && common < len(parts)-1 && dirs[common] == parts[common] {
+		common++
+	}
+	rel := strings.Repeat("../", len(dirs)-common)
+	return rel + strings.Join(parts[common:], "/")
+}
@@ -0,0 +1,180 @@
+package split
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/yaml.v2"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0}
+paths:
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+              example: {$ref: not a reference}
+        "404": {$ref: '#/components/responses/NotFound'}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name: {type: string}
+        manager: {$ref: '#/components/schemas/User'}
+        pet: {$ref: '#/components/schemas/Pet'}
+    Pet:
+      oneOf:
+      - {$ref: '#/components/schemas/Cat'}
+      discriminator:
+        propertyName: type
+        mapping: {cat: '#/components/schemas/Cat'}
+    Cat:
+      type: object
+      properties:
+        type: {type: string}
+        name: {$ref: '#/components/schemas/User/properties/name'}
+    Thing:
+      type: object
+      properties:
+        example: {$ref: '#/components/schemas/User'}
+        mapping: {$ref: '#/components/schemas/Pet'}
+  responses:
+    NotFound: {description: Not found}
+`
+
+func TestSplit(t *testing.T) {
+	Convey("Split", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+		files, err := Files(oapi)
+		So(err, ShouldBeNil)
+
+		Convey("should split paths and components", func() {
+			So(openapi3.SortedKeys(files), ShouldResemble, []string{
+				"components/responses/NotFound.yaml",
+				"components/schemas/Cat.yaml",
+				"components/schemas/Pet.yaml",
+				"components/schemas/Thing.yaml",
+				"components/schemas/User.yaml",
+				"openapi.yaml",
+				"paths/users_{id}.yaml",
+			})
+			So(string(files["paths/users_{id}.yaml"]), ShouldEqual, `get:
+  responses:
+    "200":
+      description: OK
+      content:
+        application/json:
+          schema:
+            $ref: ../components/schemas/User.yaml
+          example:
+            $ref: not a reference
+    "404":
+      $ref: ../components/responses/NotFound.yaml
+`)
+			So(string(files["components/schemas/Pet.yaml"]), ShouldEqual, `discriminator:
+  mapping:
+    cat: Cat.yaml
+  propertyName: type
+oneOf:
+- $ref: Cat.yaml
+`)
+			So(string(files["components/schemas/Cat.yaml"]), ShouldContainSubstring, "$ref: User.yaml#/properties/name")
+			So(string(files["components/schemas/Thing.yaml"]), ShouldEqual, `properties:
+  example:
+    $ref: User.yaml
+  mapping:
+    $ref: Pet.yaml
+type: object
+`)
+			So(string(files["openapi.yaml"]), ShouldContainSubstring, `paths:
+  /users/{id}:
+    $ref: paths/users_%7Bid%7D.yaml
+`)
+		})
+
+		Convey("should keep files in their directories", func() {
+			used := map[string]bool{}
+			So(pathFile("/..", used), ShouldEqual, "paths/__.yaml")
+			So(pathFile("/", used), ShouldEqual, "paths/root.yaml")
+			So(fileName("../User"), ShouldEqual, ".._User")
+			So(fileName(".."), ShouldEqual, "__")
+			So(escapeRef("../paths/users_{id}.yaml"), ShouldEqual, "../paths/users_%7Bid%7D.yaml")
+		})
+
+		Convey("should bundle split files", func() {
+			dir, err := ioutil.TempDir("", "split")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+			for name, data := range files {
+				file := filepath.Join(dir, filepath.FromSlash(name))
+				So(os.MkdirAll(filepath.Dir(file), 0755), ShouldBeNil)
+				So(ioutil.WriteFile(file, data, 0644), ShouldBeNil)
+			}
+
+			bundled, err := Bundle(filepath.Join(dir, RootFile))
+			So(err, ShouldBeNil)
+			data, err := yaml.Marshal(bundled)
+			So(err, ShouldBeNil)
+			expected, err := yaml.Marshal(oapi)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, string(expected))
+		})
+
+		Convey("should inline external references", func() {
+			dir, err := ioutil.TempDir("", "split")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+			So(ioutil.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte(`
+openapi: 3.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200": {$ref: 'responses.yaml#/OK'}
+`), 0644), ShouldBeNil)
+			So(ioutil.WriteFile(filepath.Join(dir, "responses.yaml"), []byte(`
+OK:
+  description: OK
+  content:
+    application/json:
+      schema: {$ref: '#/User'}
+User: {type: object}
+`), 0644), ShouldBeNil)
+
+			bundled, err := Bundle(filepath.Join(dir, "openapi.yaml"))
+			So(err, ShouldBeNil)
+			data, err := yaml.Marshal(bundled)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, `openapi: 3.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+`)
+
+			So(ioutil.WriteFile(filepath.Join(dir, "responses.yaml"), []byte(`
+OK: {$ref: '#/OK'}
+`), 0644), ShouldBeNil)
+			_, err = Bundle(filepath.Join(dir, "openapi.yaml"))
+			So(err, ShouldBeError, filepath.Join(dir, "responses.yaml")+": circular reference #/OK")
+		})
+	})
+}
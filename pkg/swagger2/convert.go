@@ -0,0 +1,616 @@
+package swagger2
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// Warning reports a construct of the OpenAPI document that cannot be
+// represented in Swagger 2.0, and is dropped or approximated.
+type Warning struct {
+	// Pointer is the JSON pointer of the construct in the OpenAPI document.
+	Pointer string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Pointer, w.Message)
+}
+
+const (
+	jsonMediaType           = "application/json"
+	formURLEncodedMediaType = "application/x-www-form-urlencoded"
+	multipartFormMediaType  = "multipart/form-data"
+)
+
+// e.g. https://spdx.org/licenses/MIT.html
+const spdxLicenseURL = "https://spdx.org/licenses/"
+
+// schemaKeywords are the keywords of Swagger 2.0 schema objects.
+var schemaKeywords = map[string]bool{
+	"$ref": true, "format": true, "title": true, "description": true,
+	"default": true, "multipleOf": true, "maximum": true,
+	"exclusiveMaximum": true, "minimum": true, "exclusiveMinimum": true,
+	"maxLength": true, "minLength": true, "pattern": true, "maxItems": true,
+	"minItems": true, "uniqueItems": true, "maxProperties": true,
+	"minProperties": true, "required": true, "enum": true,
+	"additionalProperties": true, "type": true, "items": true, "allOf": true,
+	"properties": true, "discriminator": true, "readOnly": true, "xml": true,
+	"externalDocs": true, "example": true,
+}
+
+// parameterKeywords are the type and validation keywords of Swagger 2.0
+// non-body parameters and items objects.
+var parameterKeywords = map[string]bool{
+	"type": true, "format": true, "items": true, "default": true,
+	"maximum": true, "exclusiveMaximum": true, "minimum": true,
+	"exclusiveMinimum": true, "maxLength": true, "minLength": true,
+	"pattern": true, "maxItems": true, "minItems": true, "uniqueItems": true,
+	"enum": true, "multipleOf": true,
+}
+
+type converter struct {
+	oapi     *openapi3.OpenAPIObject
+	doc      *Document
+	warnings []Warning
+
+	// bodyParameters records the request body components converted to body
+	// parameter components.
+	bodyParameters map[string]bool
+}
+
+func (c *converter) warn(pointer string, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, Warning{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// Convert converts the OpenAPI document to a Swagger 2.0 document. Constructs
+// that cannot be represented, e.g. callbacks, links and multiple media types
+// with different schemas, are reported as warnings.
+func Convert(oapi *openapi3.OpenAPIObject) (*Document, []Warning) {
+	c := &converter{
+		oapi: oapi,
+		doc: &Document{
+			Swagger:             Version,
+			Paths:               map[string]*PathItemObject{},
+			Definitions:         map[string]interface{}{},
+			Parameters:          map[string]*ParameterObject{},
+			Responses:           map[string]*ResponseObject{},
+			SecurityDefinitions: map[string]*SecuritySchemeObject{},
+			Security:            oapi.Security,
+			Tags:                oapi.Tags,
+			ExternalDocs:        oapi.ExternalDocs,
+			Extensions:          oapi.Extensions,
+		},
+		bodyParameters: map[string]bool{},
+	}
+
+	c.convertInfo()
+	c.convertServers()
+	c.convertComponents()
+
+	for _, path := range openapi3.SortedKeys(oapi.Paths) {
+		item := oapi.Paths[path]
+		c.doc.Paths[path] = c.convertPathItem(openapi3.Pointer("paths", path), &item)
+	}
+	for _, name := range openapi3.SortedKeys(oapi.Webhooks) {
+		c.warn(openapi3.Pointer("webhooks", name), "webhooks are not supported")
+	}
+
+	return c.doc, c.warnings
+}
+
+func (c *converter) convertInfo() {
+	info := c.oapi.Info
+	c.doc.Info = InfoObject{
+		Title:          info.Title,
+		Description:    info.Description,
+		TermsOfService: info.TermsOfService,
+		Contact:        info.Contact,
+		Version:        info.Version,
+		Extensions:     info.Extensions,
+	}
+	if info.Summary != "" {
+		c.warn("/info/summary", "summary is not supported")
+	}
+	if info.License != nil {
+		license := &LicenseObject{Name: info.License.Name, URL: info.License.URL}
+		if license.URL == "" && info.License.Identifier != "" {
+			license.URL = spdxLicenseURL + info.License.Identifier + ".html"
+		}
+		c.doc.Info.License = license
+	}
+}
+
+// convertServers converts the URL of the first server to host, base path and
+// schemes. Schemes of other servers with the same host and base path are
+// included.
+func (c *converter) convertServers() {
+	var host, basePath string
+	for i, server := range c.oapi.Servers {
+		u, err := url.Parse(expandServerURL(server))
+		if err != nil {
+			c.warn(openapi3.Pointer("servers", fmt.Sprint(i)), "invalid server URL: %v", err)
+			continue
+		}
+		path := strings.TrimSuffix(u.Path, "/")
+
+		if i == 0 {
+			host, basePath = u.Host, path
+			c.doc.Host, c.doc.BasePath = host, path
+		} else if u.Host != host || path != basePath {
+			c.warn(openapi3.Pointer("servers", fmt.Sprint(i)), "only servers with the host and base path of the first server are supported")
+			continue
+		}
+		if u.Scheme != "" && !containsString(c.doc.Schemes, u.Scheme) {
+			c.doc.Schemes = append(c.doc.Schemes, u.Scheme)
+		}
+	}
+	if c.doc.BasePath == "" && len(c.oapi.Servers) > 0 {
+		c.doc.BasePath = "/"
+	}
+}
+
+// expandServerURL substitutes the server variables with their default values.
+func expandServerURL(server openapi3.ServerObject) string {
+	u := server.URL
+	for name, variable := range server.Variables {
+		u = strings.Replace(u, "{"+name+"}", variable.Default, -1)
+	}
+	return u
+}
+
+func (c *converter) convertComponents() {
+	components := c.oapi.Components
+
+	for _, id := range openapi3.SortedKeys(components.Schemas) {
+		var schema interface{}
+		if s := components.Schemas[id]; s != nil {
+			schema = *s
+		}
+		c.doc.Definitions[id] = c.convertSchema(openapi3.Pointer("components", "schemas", id), schema)
+	}
+
+	for _, id := range openapi3.SortedKeys(components.Parameters) {
+		ptr := openapi3.Pointer("components", "parameters", id)
+		if param := c.convertParameter(ptr, components.Parameters[id]); param != nil {
+			c.doc.Parameters[id] = param
+		}
+	}
+
+	for _, id := range openapi3.SortedKeys(components.RequestBodies) {
+		ptr := openapi3.Pointer("components", "requestBodies", id)
+		if _, exists := c.doc.Parameters[id]; exists {
+			c.warn(ptr, "request body has the same ID as parameter %q, and is inlined", id)
+			continue
+		}
+		params, _ := c.convertRequestBody(ptr, components.RequestBodies[id])
+		if len(params) != 1 || params[0].(*ParameterObject).Location != "body" {
+			// Form parameters are inlined in operations.
+			continue
+		}
+		c.doc.Parameters[id] = params[0].(*ParameterObject)
+		c.bodyParameters[id] = true
+	}
+
+	for _, id := range openapi3.SortedKeys(components.Responses) {
+		ptr := openapi3.Pointer("components", "responses", id)
+		response, _ := c.convertResponse(ptr, components.Responses[id])
+		c.doc.Responses[id] = response
+	}
+
+	for _, id := range openapi3.SortedKeys(components.SecuritySchemes) {
+		ptr := openapi3.Pointer("components", "securitySchemes", id)
+		if scheme := c.convertSecurityScheme(ptr, components.SecuritySchemes[id]); scheme != nil {
+			c.doc.SecurityDefinitions[id] = scheme
+		}
+	}
+
+	for _, id := range openapi3.SortedKeys(components.Callbacks) {
+		c.warn(openapi3.Pointer("components", "callbacks", id), "callbacks are not supported")
+	}
+	for _, id := range openapi3.SortedKeys(components.PathItems) {
+		c.warn(openapi3.Pointer("components", "pathItems", id), "path item components are not supported")
+	}
+}
+
+func (c *converter) convertSecurityScheme(ptr string, scheme *openapi3.SecuritySchemeObject) *SecuritySchemeObject {
+	result := &SecuritySchemeObject{
+		Description: scheme.Description,
+		Extensions:  scheme.Extensions,
+	}
+	switch {
+	case scheme.Type == openapi3.SecuritySchemeTypeAPIKey && scheme.APIKeyLocation == openapi3.SecuritySchemeAPIKeyLocationCookie:
+		c.warn(ptr, "API keys in cookies are not supported")
+		return nil
+	case scheme.Type == openapi3.SecuritySchemeTypeAPIKey:
+		result.Type = "apiKey"
+		result.Name = scheme.APIKeyName
+		result.Location = string(scheme.APIKeyLocation)
+	case scheme.Type == openapi3.SecuritySchemeTypeHTTP && strings.EqualFold(scheme.HTTPAuthScheme, "basic"):
+		result.Type = "basic"
+	case scheme.Type == openapi3.SecuritySchemeTypeHTTP && strings.EqualFold(scheme.HTTPAuthScheme, "bearer"):
+		c.warn(ptr, "bearer authentication is converted to an API key in the Authorization header")
+		result.Type = "apiKey"
+		result.Name = "Authorization"
+		result.Location = "header"
+	default:
+		c.warn(ptr, "security scheme %s %s is not supported", scheme.Type, scheme.HTTPAuthScheme)
+		return nil
+	}
+	return result
+}
+
+func (c *converter) convertPathItem(ptr string, item *openapi3.PathItemObject) *PathItemObject {
+	result := &PathItemObject{Ref: item.Ref, Extensions: item.Extensions}
+	if item.Summary != "" || item.Description != "" {
+		c.warn(ptr, "path item summary and description are not supported")
+	}
+	if len(item.Servers) > 0 {
+		c.warn(ptr+"/servers", "path item servers are not supported")
+	}
+	for i := range item.Parameters {
+		if param := c.convertParameter(ptr+openapi3.Pointer("parameters", fmt.Sprint(i)), &item.Parameters[i]); param != nil {
+			result.Parameters = append(result.Parameters, param)
+		}
+	}
+
+	for _, method := range openapi3.Methods {
+		op := item.GetOperation(method)
+		if op == nil {
+			continue
+		}
+		opPtr := ptr + openapi3.Pointer(strings.ToLower(method))
+		converted := c.convertOperation(opPtr, op)
+		switch strings.ToLower(method) {
+		case "get":
+			result.Get = converted
+		case "put":
+			result.Put = converted
+		case "post":
+			result.Post = converted
+		case "delete":
+			result.Delete = converted
+		case "options":
+			result.Options = converted
+		case "head":
+			result.Head = converted
+		case "patch":
+			result.Patch = converted
+		default:
+			c.warn(opPtr, "%s operations are not supported", method)
+		}
+	}
+	return result
+}
+
+func (c *converter) convertOperation(ptr string, op *openapi3.OperationObject) *OperationObject {
+	result := &OperationObject{
+		Tags:         op.Tags,
+		Summary:      op.Summary,
+		Description:  op.Description,
+		ExternalDocs: op.ExternalDocs,
+		ID:           op.ID,
+		Responses:    map[string]Response{},
+		Deprecated:   op.Deprecated,
+		Security:     op.Security,
+		Extensions:   op.Extensions,
+	}
+	if len(op.Servers) > 0 {
+		c.warn(ptr+"/servers", "operation servers are not supported")
+	}
+
+	for i, param := range op.Parameters {
+		paramPtr := ptr + openapi3.Pointer("parameters", fmt.Sprint(i))
+		if id, ok := openapi3.RefID(param, "#/components/parameters/"); ok {
+			if _, exists := c.doc.Parameters[id]; exists {
+				result.Parameters = append(result.Parameters, makeRef("parameters", id))
+			}
+			continue
+		}
+		if paramObj, ok := param.(*openapi3.ParameterObject); ok {
+			if converted := c.convertParameter(paramPtr, paramObj); converted != nil {
+				result.Parameters = append(result.Parameters, converted)
+			}
+		}
+	}
+
+	if op.RequestBody != nil {
+		bodyPtr := ptr + "/requestBody"
+		if id, ok := openapi3.RefID(op.RequestBody, "#/components/requestBodies/"); ok && c.bodyParameters[id] {
+			result.Parameters = append(result.Parameters, makeRef("parameters", id))
+			result.Consumes = openapi3.SortedKeys(c.oapi.Components.RequestBodies[id].Content)
+		} else if body := c.oapi.ResolveRequestBody(op.RequestBody); body != nil {
+			params, consumes := c.convertRequestBody(bodyPtr, body)
+			result.Parameters = append(result.Parameters, params...)
+			result.Consumes = consumes
+		}
+	}
+
+	produces := map[string]bool{}
+	for _, statusCode := range openapi3.SortedKeys(op.Responses) {
+		response := op.Responses[statusCode]
+		responsePtr := ptr + openapi3.Pointer("responses", statusCode)
+		if id, ok := openapi3.RefID(response, "#/components/responses/"); ok {
+			result.Responses[statusCode] = makeRef("responses", id)
+			if responseObj := c.oapi.Components.Responses[id]; responseObj != nil {
+				for mediaType := range responseObj.Content {
+					produces[mediaType] = true
+				}
+			}
+			continue
+		}
+		if responseObj, ok := response.(*openapi3.ResponseObject); ok {
+			converted, mediaTypes := c.convertResponse(responsePtr, responseObj)
+			result.Responses[statusCode] = converted
+			for _, mediaType := range mediaTypes {
+				produces[mediaType] = true
+			}
+		}
+	}
+	result.Produces = openapi3.SortedKeys(produces)
+
+	for _, key := range openapi3.SortedKeys(op.Callbacks) {
+		c.warn(ptr+openapi3.Pointer("callbacks", key), "callbacks are not supported")
+	}
+
+	return result
+}
+
+// convertParameter converts a parameter to a non-body parameter. Cookie
+// parameters are not supported, and nil is returned.
+func (c *converter) convertParameter(ptr string, param *openapi3.ParameterObject) *ParameterObject {
+	if param.Location == openapi3.ParameterLocationCookie {
+		c.warn(ptr, "cookie parameters are not supported")
+		return nil
+	}
+
+	result := &ParameterObject{
+		Name:        param.Name,
+		Location:    string(param.Location),
+		Description: param.Description,
+		Required:    param.Required,
+		Keywords:    c.parameterKeywords(ptr+"/schema", param.Schema),
+	}
+	if result.Keywords["type"] == "array" && param.Location == openapi3.ParameterLocationQuery {
+		// Query arrays are serialized with repeated parameters by default.
+		result.Keywords["collectionFormat"] = "multi"
+	}
+	if param.AllowEmptyValue {
+		result.Keywords["allowEmptyValue"] = true
+	}
+	if param.Deprecated {
+		c.warn(ptr+"/deprecated", "deprecated parameters are not supported")
+	}
+	if param.Style != "" || param.Explode != nil {
+		c.warn(ptr, "parameter style is not supported")
+	}
+	if len(param.Content) > 0 {
+		c.warn(ptr+"/content", "parameter content is not supported")
+	}
+	for key, value := range param.Extensions {
+		result.Keywords[key] = value
+	}
+	return result
+}
+
+// parameterKeywords returns the type and validation keywords of a non-body
+// parameter or items object with the schema.
+func (c *converter) parameterKeywords(ptr string, schema interface{}) map[string]interface{} {
+	resolved, _ := c.oapi.ResolveSchema(schema)
+	resolved, _ = openapi3.ConvertSchema(resolved, openapi3.Version30).(map[string]interface{})
+
+	keywords := map[string]interface{}{}
+	for key, value := range resolved {
+		if parameterKeywords[key] {
+			keywords[key] = value
+		}
+	}
+
+	switch keywords["type"] {
+	case nil:
+		keywords["type"] = "string"
+	case "object":
+		c.warn(ptr, "object parameters are not supported, and are converted to strings")
+		keywords = map[string]interface{}{"type": "string"}
+	case "array":
+		keywords["items"] = c.parameterKeywords(ptr+"/items", resolved["items"])
+	}
+	return keywords
+}
+
+// convertRequestBody converts a request body to a body parameter, or form
+// data parameters for form media types. The media types consumed are
+// returned.
+func (c *converter) convertRequestBody(ptr string, body *openapi3.RequestBodyObject) ([]Parameter, []string) {
+	mediaTypes := openapi3.SortedKeys(body.Content)
+	if len(mediaTypes) == 0 {
+		return nil, nil
+	}
+	mediaType := c.selectMediaType(ptr+"/content", body.Content)
+	schema := body.Content[mediaType].Schema
+	for _, mt := range mediaTypes {
+		if len(body.Content[mt].Examples) > 0 {
+			c.warn(ptr+openapi3.Pointer("content", mt, "examples"), "request body examples are not supported")
+		} else if body.Content[mt].Example != nil {
+			c.warn(ptr+openapi3.Pointer("content", mt, "example"), "request body examples are not supported")
+		}
+	}
+
+	if mediaType != formURLEncodedMediaType && mediaType != multipartFormMediaType {
+		return []Parameter{&ParameterObject{
+			Name:        "body",
+			Location:    "body",
+			Description: body.Description,
+			Required:    body.Required,
+			Schema:      c.convertSchema(ptr+openapi3.Pointer("content", mediaType, "schema"), schema),
+		}}, mediaTypes
+	}
+
+	resolved, _ := c.oapi.ResolveSchema(schema)
+	var required []interface{}
+	if resolved != nil {
+		required, _ = resolved["required"].([]interface{})
+	}
+	properties, _ := resolved["properties"].(map[string]interface{})
+
+	var params []Parameter
+	for _, name := range openapi3.SortedKeys(properties) {
+		propPtr := ptr + openapi3.Pointer("content", mediaType, "schema", "properties", name)
+		param := &ParameterObject{
+			Name:     name,
+			Location: "formData",
+			Required: containsValue(required, name),
+		}
+		if prop, ok := properties[name].(map[string]interface{}); ok && prop["format"] == "binary" {
+			param.Keywords = map[string]interface{}{"type": "file"}
+		} else {
+			param.Keywords = c.parameterKeywords(propPtr, properties[name])
+		}
+		if prop, ok := properties[name].(map[string]interface{}); ok {
+			param.Description, _ = prop["description"].(string)
+		}
+		params = append(params, param)
+	}
+	return params, mediaTypes
+}
+
+// convertResponse converts a response, returning the media types produced.
+func (c *converter) convertResponse(ptr string, response *openapi3.ResponseObject) (*ResponseObject, []string) {
+	result := &ResponseObject{Description: response.Description, Extensions: response.Extensions}
+	if len(response.Links) > 0 {
+		c.warn(ptr+"/links", "links are not supported")
+	}
+	for _, name := range openapi3.SortedKeys(response.Headers) {
+		header, _ := response.Headers[name].(map[string]interface{})
+		converted := c.parameterKeywords(ptr+openapi3.Pointer("headers", name, "schema"), header["schema"])
+		if description, ok := header["description"]; ok {
+			converted["description"] = description
+		}
+		if result.Headers == nil {
+			result.Headers = map[string]interface{}{}
+		}
+		result.Headers[name] = converted
+	}
+
+	mediaTypes := openapi3.SortedKeys(response.Content)
+	if len(mediaTypes) == 0 {
+		return result, nil
+	}
+	mediaType := c.selectMediaType(ptr+"/content", response.Content)
+	result.Schema = c.convertSchema(ptr+openapi3.Pointer("content", mediaType, "schema"), response.Content[mediaType].Schema)
+
+	for _, mt := range mediaTypes {
+		example := response.Content[mt].Example
+		if keys := openapi3.SortedKeys(response.Content[mt].Examples); len(keys) > 0 {
+			example = response.Content[mt].Examples[keys[0]].Value
+		}
+		if example != nil {
+			if result.Examples == nil {
+				result.Examples = map[string]interface{}{}
+			}
+			result.Examples[mt] = example
+		}
+	}
+	return result, mediaTypes
+}
+
+// selectMediaType returns the media type whose schema is converted, preferring
+// JSON. Other media types with different schemas are reported.
+func (c *converter) selectMediaType(ptr string, content map[string]openapi3.MediaTypeObject) string {
+	mediaTypes := openapi3.SortedKeys(content)
+	selected := mediaTypes[0]
+	if _, hasJSON := content[jsonMediaType]; hasJSON {
+		selected = jsonMediaType
+	}
+
+	selectedSchema, _ := openapi3.ToDocument(content[selected].Schema)
+	for _, mediaType := range mediaTypes {
+		if mediaType == selected {
+			continue
+		}
+		schema, _ := openapi3.ToDocument(content[mediaType].Schema)
+		if !jsonschema.Equal(schema, selectedSchema) {
+			c.warn(ptr+openapi3.Pointer(mediaType), "multiple media types with different schemas are not supported, and only the schema of %s is used", selected)
+		}
+	}
+	return selected
+}
+
+// convertSchema converts a schema to a Swagger 2.0 schema: references to
+// component schemas are converted to references to definitions, nullable to
+// x-nullable, and other unsupported keywords are removed.
+func (c *converter) convertSchema(ptr string, schema interface{}) interface{} {
+	if schema == nil {
+		return nil
+	}
+	if id, ok := openapi3.RefID(schema, "#/components/schemas/"); ok {
+		return makeRef("definitions", id)
+	}
+	obj, ok := openapi3.SchemaMap(schema)
+	if !ok {
+		return schema
+	}
+	obj, _ = openapi3.ConvertSchema(obj, openapi3.Version30).(map[string]interface{})
+
+	result := map[string]interface{}{}
+	for _, key := range openapi3.SortedKeys(obj) {
+		value := obj[key]
+		switch {
+		case key == "nullable":
+			result["x-nullable"] = value
+		case key == "discriminator":
+			discriminator, _ := value.(map[string]interface{})
+			result[key] = discriminator["propertyName"]
+			if _, hasMapping := discriminator["mapping"]; hasMapping {
+				c.warn(ptr+"/discriminator/mapping", "discriminator mappings are not supported")
+			}
+		case key == "properties":
+			properties, _ := value.(map[string]interface{})
+			converted := map[string]interface{}{}
+			for name, prop := range properties {
+				converted[name] = c.convertSchema(ptr+openapi3.Pointer(key, name), prop)
+			}
+			result[key] = converted
+		case key == "allOf":
+			schemas, _ := value.([]interface{})
+			converted := make([]interface{}, len(schemas))
+			for i, sub := range schemas {
+				converted[i] = c.convertSchema(ptr+openapi3.Pointer(key, fmt.Sprint(i)), sub)
+			}
+			result[key] = converted
+		case key == "items" || key == "additionalProperties":
+			if _, isBool := value.(bool); isBool {
+				result[key] = value
+			} else {
+				result[key] = c.convertSchema(ptr+openapi3.Pointer(key), value)
+			}
+		case schemaKeywords[key] || strings.HasPrefix(key, "x-"):
+			result[key] = value
+		default:
+			c.warn(ptr+openapi3.Pointer(key), "schema keyword %s is not supported", key)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, str string) bool {
+	for _, value := range values {
+		if value == str {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package swagger2
+
+import "github.com/skygeario/openapi3-gen/pkg/openapi3"
+
+// Version is the Swagger specification version of converted documents.
+const Version = "2.0"
+
+// Document is a Swagger 2.0 document.
+type Document struct {
+	Swagger             string                                `yaml:"swagger"`
+	Info                InfoObject                            `yaml:"info"`
+	Host                string                                `yaml:"host,omitempty"`
+	BasePath            string                                `yaml:"basePath,omitempty"`
+	Schemes             []string                              `yaml:"schemes,omitempty"`
+	Consumes            []string                              `yaml:"consumes,omitempty"`
+	Produces            []string                              `yaml:"produces,omitempty"`
+	Paths               map[string]*PathItemObject            `yaml:"paths"`
+	Definitions         map[string]interface{}                `yaml:"definitions,omitempty"`
+	Parameters          map[string]*ParameterObject           `yaml:"parameters,omitempty"`
+	Responses           map[string]*ResponseObject            `yaml:"responses,omitempty"`
+	SecurityDefinitions map[string]*SecuritySchemeObject      `yaml:"securityDefinitions,omitempty"`
+	Security            openapi3.SecurityRequirements         `yaml:"security,omitempty"`
+	Tags                []openapi3.TagObject                  `yaml:"tags,omitempty"`
+	ExternalDocs        *openapi3.ExternalDocumentationObject `yaml:"externalDocs,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+type InfoObject struct {
+	Title          string                  `yaml:"title"`
+	Description    string                  `yaml:"description,omitempty"`
+	TermsOfService string                  `yaml:"termsOfService,omitempty"`
+	Contact        *openapi3.ContactObject `yaml:"contact,omitempty"`
+	License        *LicenseObject          `yaml:"license,omitempty"`
+	Version        string                  `yaml:"version"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+type LicenseObject struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url,omitempty"`
+}
+
+type PathItemObject struct {
+	Ref        string           `yaml:"$ref,omitempty"`
+	Get        *OperationObject `yaml:"get,omitempty"`
+	Put        *OperationObject `yaml:"put,omitempty"`
+	Post       *OperationObject `yaml:"post,omitempty"`
+	Delete     *OperationObject `yaml:"delete,omitempty"`
+	Options    *OperationObject `yaml:"options,omitempty"`
+	Head       *OperationObject `yaml:"head,omitempty"`
+	Patch      *OperationObject `yaml:"patch,omitempty"`
+	Parameters []Parameter      `yaml:"parameters,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+type OperationObject struct {
+	Tags         []string                              `yaml:"tags,omitempty"`
+	Summary      string                                `yaml:"summary,omitempty"`
+	Description  string                                `yaml:"description,omitempty"`
+	ExternalDocs *openapi3.ExternalDocumentationObject `yaml:"externalDocs,omitempty"`
+	ID           string                                `yaml:"operationId,omitempty"`
+	Consumes     []string                              `yaml:"consumes,omitempty"`
+	Produces     []string                              `yaml:"produces,omitempty"`
+	Parameters   []Parameter                           `yaml:"parameters,omitempty"`
+	Responses    map[string]Response                   `yaml:"responses"`
+	Deprecated   bool                                  `yaml:"deprecated,omitempty"`
+	Security     openapi3.SecurityRequirements         `yaml:"security,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// Parameter is a *ParameterObject or a ReferenceObject.
+type Parameter interface{}
+
+type ParameterObject struct {
+	Name        string `yaml:"name"`
+	Location    string `yaml:"in"`
+	Description string `yaml:"description,omitempty"`
+	Required    bool   `yaml:"required,omitempty"`
+	// Schema is the schema of body parameters.
+	Schema interface{} `yaml:"schema,omitempty"`
+
+	// Keywords are the type and validation keywords of other parameters,
+	// and extensions.
+	Keywords map[string]interface{} `yaml:",inline"`
+}
+
+// Response is a *ResponseObject or a ReferenceObject.
+type Response interface{}
+
+type ResponseObject struct {
+	Description string      `yaml:"description"`
+	Schema      interface{} `yaml:"schema,omitempty"`
+	// Headers are header objects, with the type and validation keywords of
+	// the header schemas.
+	Headers map[string]interface{} `yaml:"headers,omitempty"`
+	// Examples are example values by media type.
+	Examples map[string]interface{} `yaml:"examples,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+type SecuritySchemeObject struct {
+	Type        string `yaml:"type"`
+	Description string `yaml:"description,omitempty"`
+	Name        string `yaml:"name,omitempty"`
+	Location    string `yaml:"in,omitempty"`
+
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// ReferenceObject is a JSON reference, e.g. to a definition.
+type ReferenceObject map[string]interface{}
+
+func makeRef(kind string, id string) ReferenceObject {
+	return ReferenceObject{"$ref": "#/" + kind + "/" + id}
+}
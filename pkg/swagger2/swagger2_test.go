@@ -0,0 +1,187 @@
+package swagger2
+
+import (
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDocument = `
+openapi: 3.0.0
+info: {title: Test API, version: 1.0.0, license: {name: MIT}}
+servers:
+- url: https://{env}.example.com/v1/
+  variables:
+    env: {default: api}
+- url: http://api.example.com/v1
+- url: https://staging.example.com/v1
+paths:
+  /users:
+    get:
+      parameters:
+      - {name: ids, in: query, schema: {type: array, items: {type: string}}}
+      - {name: session, in: cookie, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/User'}}
+              examples:
+                Test: {value: [{name: Test}]}
+      callbacks:
+        Created: {}
+    post:
+      requestBody:
+        $ref: '#/components/requestBodies/User'
+      responses:
+        "201": {$ref: '#/components/responses/User'}
+  /users/{id}/avatar:
+    put:
+      deprecated: true
+      parameters:
+      - $ref: '#/components/parameters/ID'
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file]
+              properties:
+                file: {type: string, format: binary}
+                caption: {type: string, maxLength: 100}
+      responses:
+        "204":
+          description: Updated
+          headers:
+            ETag: {description: Version of the avatar, schema: {type: string}}
+          links:
+            User: {operationId: getUser}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name: {type: string, nullable: true}
+        manager: {$ref: '#/components/schemas/User'}
+        role: {oneOf: [{type: string}, {type: integer}]}
+  parameters:
+    ID: {name: id, in: path, required: true, schema: {type: string, pattern: '^[a-z]+$'}}
+  requestBodies:
+    User:
+      required: true
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/User'}
+          examples:
+            Test: {value: {name: Test}}
+        application/xml:
+          schema: {type: object}
+  responses:
+    User:
+      description: User
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/User'}
+  securitySchemes:
+    access_token: {type: http, scheme: bearer, bearerFormat: JWT}
+    basic: {type: http, scheme: basic}
+    session: {type: apiKey, in: cookie, name: session}
+`
+
+const expectedDocument = `
+swagger: "2.0"
+info:
+  title: Test API
+  license: {name: MIT}
+  version: 1.0.0
+host: api.example.com
+basePath: /v1
+schemes: [https, http]
+paths:
+  /users:
+    get:
+      produces: [application/json]
+      parameters:
+      - {name: ids, in: query, type: array, items: {type: string}, collectionFormat: multi}
+      responses:
+        "200":
+          description: OK
+          schema: {type: array, items: {$ref: '#/definitions/User'}}
+          examples:
+            application/json: [{name: Test}]
+    post:
+      consumes: [application/json, application/xml]
+      produces: [application/json]
+      parameters:
+      - {$ref: '#/parameters/User'}
+      responses:
+        "201": {$ref: '#/responses/User'}
+  /users/{id}/avatar:
+    put:
+      deprecated: true
+      consumes: [multipart/form-data]
+      parameters:
+      - {$ref: '#/parameters/ID'}
+      - {name: caption, in: formData, type: string, maxLength: 100}
+      - {name: file, in: formData, required: true, type: file}
+      responses:
+        "204":
+          description: Updated
+          headers:
+            ETag: {description: Version of the avatar, type: string}
+definitions:
+  User:
+    type: object
+    properties:
+      name: {type: string, x-nullable: true}
+      manager: {$ref: '#/definitions/User'}
+      role: {}
+parameters:
+  ID: {name: id, in: path, required: true, type: string, pattern: '^[a-z]+$'}
+  User:
+    name: body
+    in: body
+    required: true
+    schema: {$ref: '#/definitions/User'}
+responses:
+  User:
+    description: User
+    schema: {$ref: '#/definitions/User'}
+securityDefinitions:
+  access_token: {type: apiKey, name: Authorization, in: header}
+  basic: {type: basic}
+`
+
+func TestConvert(t *testing.T) {
+	Convey("Convert", t, func() {
+		oapi, err := openapi3.Load([]byte(testDocument))
+		So(err, ShouldBeNil)
+
+		doc, warnings := Convert(oapi)
+
+		actual, err := openapi3.ToDocument(doc)
+		So(err, ShouldBeNil)
+		expected, err := openapi3.ReadDocument([]byte(expectedDocument))
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, expected)
+
+		var messages []string
+		for _, warning := range warnings {
+			messages = append(messages, warning.String())
+		}
+		So(messages, ShouldResemble, []string{
+			"/servers/2: only servers with the host and base path of the first server are supported",
+			"/components/schemas/User/properties/role/oneOf: schema keyword oneOf is not supported",
+			"/components/requestBodies/User/content/application~1xml: multiple media types with different schemas are not supported, and only the schema of application/json is used",
+			"/components/requestBodies/User/content/application~1json/examples: request body examples are not supported",
+			"/components/securitySchemes/access_token: bearer authentication is converted to an API key in the Authorization header",
+			"/components/securitySchemes/session: API keys in cookies are not supported",
+			"/paths/~1users/get/parameters/1: cookie parameters are not supported",
+			"/paths/~1users/get/callbacks/Created: callbacks are not supported",
+			"/paths/~1users~1{id}~1avatar/put/responses/204/links: links are not supported",
+		})
+	})
+}
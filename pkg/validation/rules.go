@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"sort"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// checkResponseDescriptions reports responses with empty descriptions, which
+// the meta-schema accepts but the specification requires.
+func checkResponseDescriptions(doc interface{}) []jsonschema.ValidationError {
+	var errs []jsonschema.ValidationError
+
+	checkResponse := func(pointer string, response interface{}) {
+		obj, ok := response.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if description, ok := obj["description"].(string); ok && description == "" {
+			errs = append(errs, jsonschema.ValidationError{
+				Pointer: pointer + "/description",
+				Message: "response description must not be empty",
+			})
+		}
+	}
+
+	var checkPathItems func(pointer string, pathItems interface{})
+	checkPathItems = func(pointer string, pathItems interface{}) {
+		items, _ := pathItems.(map[string]interface{})
+		for _, path := range openapi3.SortedKeys(items) {
+			item, _ := items[path].(map[string]interface{})
+			for _, method := range operationMethods {
+				operation, ok := item[method].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				operationPointer := pointer + openapi3.Pointer(path, method)
+
+				responses, _ := operation["responses"].(map[string]interface{})
+				for code, response := range responses {
+					checkResponse(operationPointer+openapi3.Pointer("responses", code), response)
+				}
+				callbacks, _ := operation["callbacks"].(map[string]interface{})
+				for key, callback := range callbacks {
+					checkPathItems(operationPointer+openapi3.Pointer("callbacks", key), callback)
+				}
+			}
+		}
+	}
+
+	root, _ := doc.(map[string]interface{})
+	checkPathItems("/paths", root["paths"])
+	checkPathItems("/webhooks", root["webhooks"])
+	checkPathItems("/x-webhooks", root["x-webhooks"])
+	components, _ := root["components"].(map[string]interface{})
+	responses, _ := components["responses"].(map[string]interface{})
+	for id, response := range responses {
+		checkResponse(openapi3.Pointer("components", "responses", id), response)
+	}
+	callbacks, _ := components["callbacks"].(map[string]interface{})
+	for id, callback := range callbacks {
+		checkPathItems(openapi3.Pointer("components", "callbacks", id), callback)
+	}
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Pointer < errs[j].Pointer
+	})
+	return errs
+}
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/skygeario/openapi3-gen/pkg/jsonschema"
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+)
+
+// openAPI30Schema is the official OpenAPI 3.0 JSON meta-schema (2019-04-02),
+// from https://spec.openapis.org/oas/3.0/schema/2019-04-02.
+//
+//go:embed openapi-3.0.json
+var openAPI30Schema []byte
+
+var (
+	metaSchemaOnce sync.Once
+	metaSchema     *jsonschema.Schema
+	metaSchemaErr  error
+)
+
+func compileMetaSchema() (*jsonschema.Schema, error) {
+	metaSchemaOnce.Do(func() {
+		var root interface{}
+		if err := json.Unmarshal(openAPI30Schema, &root); err != nil {
+			metaSchemaErr = err
+			return
+		}
+		metaSchema, metaSchemaErr = jsonschema.NewCompiler(root).Compile("#")
+	})
+	return metaSchema, metaSchemaErr
+}
+
+// ValidateDocument validates a generic OpenAPI 3.0 document against the
+// OpenAPI 3.0 meta-schema, and requirements of the specification not
+// expressed in it.
+func ValidateDocument(doc interface{}) ([]jsonschema.ValidationError, error) {
+	if obj, ok := doc.(map[string]interface{}); ok {
+		if version, ok := obj["openapi"].(string); ok && openapi3.IsVersion31(version) {
+			return nil, fmt.Errorf("validation of OpenAPI %v documents is not supported", version)
+		}
+	}
+
+	schema, err := compileMetaSchema()
+	if err != nil {
+		return nil, err
+	}
+	errs := schema.Validate(doc)
+	errs = append(errs, checkResponseDescriptions(doc)...)
+	return errs, nil
+}
@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/skygeario/openapi3-gen/pkg/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateDocument(t *testing.T) {
+	validate := func(src string) []string {
+		doc, err := openapi3.ReadDocument([]byte(src))
+		So(err, ShouldBeNil)
+		violations, err := ValidateDocument(doc)
+		So(err, ShouldBeNil)
+		var errs []string
+		for _, violation := range violations {
+			errs = append(errs, violation.Error())
+		}
+		return errs
+	}
+
+	Convey("ValidateDocument", t, func() {
+		Convey("should accept valid documents", func() {
+			So(validate(`
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /user/{id}:
+    get:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      nullable: true
+  securitySchemes:
+    api_key:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`), ShouldBeEmpty)
+		})
+
+		Convey("should report violations", func() {
+			So(validate(`
+openapi: 3.0.0
+info:
+  title: Test API
+paths:
+  /user:
+    get:
+      responses:
+        "200": {}
+`), ShouldResemble, []string{
+				"/info: missing required property \"version\"",
+				"/paths/~1user/get/responses/200: missing required property \"description\"",
+			})
+		})
+
+		Convey("should report empty response descriptions", func() {
+			So(validate(`
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /user:
+    post:
+      responses:
+        "200":
+          description: ""
+      callbacks:
+        created:
+          /hook:
+            post:
+              responses:
+                "200":
+                  description: ""
+components:
+  responses:
+    NotFound:
+      description: ""
+`), ShouldResemble, []string{
+				"/components/responses/NotFound/description: response description must not be empty",
+				"/paths/~1user/post/callbacks/created/~1hook/post/responses/200/description: response description must not be empty",
+				"/paths/~1user/post/responses/200/description: response description must not be empty",
+			})
+		})
+
+		Convey("should validate generated documents", func() {
+			oapi := openapi3.NewOpenAPIObject()
+			oapi.Info.Title = "Test API"
+			oapi.Info.Version = "1.0.0"
+			doc, err := openapi3.ToDocument(oapi)
+			So(err, ShouldBeNil)
+			violations, err := ValidateDocument(doc)
+			So(err, ShouldBeNil)
+			So(violations, ShouldBeEmpty)
+		})
+	})
+}